@@ -0,0 +1,54 @@
+// Command protogen writes a proto file for the Go package in the current directory. It is intended to be run from
+// a go:generate directive:
+//
+//	//go:generate go run github.com/activatedio/protogen/cmd/protogen -out orders.proto
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/activatedio/protogen/goinput"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "protogen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+
+	dir := flag.String("dir", ".", "directory of the Go package")
+	out := flag.String("out", "", "proto file to write, relative to the Go package directory")
+	pkg := flag.String("package", "", "proto package, defaults to the name of the Go package")
+	path := flag.String("path", "", "path other proto files import the file by, defaults to -out")
+	flag.Parse()
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+	if *path == "" {
+		*path = filepath.ToSlash(*out)
+	}
+
+	f, err := goinput.Load(*dir, goinput.LoadParams{PackageName: *pkg, Path: *path})
+	if err != nil {
+		return err
+	}
+
+	w, err := os.Create(filepath.Join(*dir, *out))
+	if err != nil {
+		return err
+	}
+
+	err = f.Write(w)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,2 @@
+// Package goinput builds proto declarations from go language inputs
+package goinput
@@ -0,0 +1,93 @@
+package goinput
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/activatedio/protogen/proto"
+)
+
+// EnumConst is a constant of a Go named integer type, such as StatusActive in
+// const ( StatusUnknown Status = iota; StatusActive ).
+type EnumConst struct {
+	Name     string
+	Value    int64
+	Comments proto.Comments
+}
+
+// FromConsts returns the enum for the Go named integer type typeName holding its constants, with the Go values as
+// the enum numbers. Value names follow the proto style guide: the type name prefix is stripped from the constant
+// name, which is then converted to upper snake case and prefixed with the type name, so StatusActive of type Status
+// becomes STATUS_ACTIVE. The value numbered zero is written first and named STATUS_UNSPECIFIED, whatever the name of
+// its constant, and is inserted if there is none. Other constants may therefore not become STATUS_UNSPECIFIED. The
+// allow_alias option is set when constants share a value. String methods of the Go type are not used, since they
+// describe values for display while enum value names must be identifiers which stay stable.
+func FromConsts(typeName string, consts ...EnumConst) (proto.Enum, error) {
+
+	prefix := upperSnakeCase(typeName) + "_"
+	unspecified := prefix + "UNSPECIFIED"
+	e := proto.NewEnum(typeName)
+
+	valueNames := make([]string, len(consts))
+	names := map[string]string{}
+	numbers := map[int64]bool{}
+	alias := false
+	zero := -1
+
+	for n, c := range consts {
+		if c.Value < math.MinInt32 || c.Value > math.MaxInt32 {
+			return nil, fmt.Errorf("enum %s: constant %s: value %d does not fit into an enum value", typeName, c.Name, c.Value)
+		}
+		name := valueName(typeName, prefix, c.Name)
+		if name == unspecified && c.Value != 0 {
+			return nil, fmt.Errorf("enum %s: constant %s: %s is kept for the zero value", typeName, c.Name, unspecified)
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("enum %s: constants %s and %s both become %s", typeName, other, c.Name, name)
+		}
+		names[name] = c.Name
+		if numbers[c.Value] {
+			alias = true
+		}
+		numbers[c.Value] = true
+		if c.Value == 0 && (zero < 0 || name == unspecified) {
+			zero = n
+		}
+		valueNames[n] = name
+	}
+
+	values := []proto.EnumValue{proto.NewEnumValue(unspecified, 0)}
+	if zero >= 0 {
+		values[0].SetComments(consts[zero].Comments)
+	}
+	for n, c := range consts {
+		if n != zero {
+			values = append(values, proto.NewEnumValue(valueNames[n], int32(c.Value)).SetComments(c.Comments))
+		}
+	}
+
+	if alias {
+		e.AddOptions(proto.NewOption("allow_alias", proto.NewBoolConstant(true)))
+	}
+
+	return e.AddValues(values...), nil
+}
+
+// valueName returns the proto name of a constant: the constant name without the type name prefix, in upper snake
+// case and prefixed with the upper snake case type name. Names already carrying the prefix are kept.
+func valueName(typeName, prefix, name string) string {
+
+	if rest, ok := strings.CutPrefix(name, typeName); ok && rest != "" {
+		if r := []rune(rest)[0]; unicode.IsUpper(r) || unicode.IsDigit(r) || r == '_' {
+			name = strings.TrimPrefix(rest, "_")
+		}
+	}
+
+	name = upperSnakeCase(name)
+	if strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return prefix + name
+}
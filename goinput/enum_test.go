@@ -0,0 +1,112 @@
+package goinput_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/activatedio/protogen"
+	"github.com/activatedio/protogen/goinput"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromConsts(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	cases := []struct {
+		name     string
+		typeName string
+		consts   []goinput.EnumConst
+		expected string
+	}{
+		{
+			name:     "zero value renamed",
+			typeName: "Status",
+			consts: []goinput.EnumConst{
+				{Name: "StatusUnknown", Value: 0},
+				{Name: "StatusActive", Value: 1},
+				{Name: "StatusHTTPError", Value: 2},
+			},
+			expected: `enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+  STATUS_HTTP_ERROR = 2;
+}
+`,
+		},
+		{
+			name:     "inserted unspecified value",
+			typeName: "OrderKind",
+			consts: []goinput.EnumConst{
+				{Name: "OrderKindRetail", Value: 1},
+				{Name: "Wholesale", Value: 2},
+				{Name: "ORDER_KIND_RETURN", Value: 3},
+			},
+			expected: `enum OrderKind {
+  ORDER_KIND_UNSPECIFIED = 0;
+  ORDER_KIND_RETAIL = 1;
+  ORDER_KIND_WHOLESALE = 2;
+  ORDER_KIND_RETURN = 3;
+}
+`,
+		},
+		{
+			name:     "zero value moved first and aliases",
+			typeName: "Level",
+			consts: []goinput.EnumConst{
+				{Name: "LevelLow", Value: -1},
+				{Name: "LevelNone", Value: 0},
+				{Name: "LevelDefault", Value: 0},
+			},
+			expected: `enum Level {
+  option allow_alias = true;
+  LEVEL_UNSPECIFIED = 0;
+  LEVEL_LOW = -1;
+  LEVEL_DEFAULT = 0;
+}
+`,
+		},
+		{
+			name:     "unspecified zero value preferred over aliases",
+			typeName: "Mode",
+			consts: []goinput.EnumConst{
+				{Name: "ModeNone", Value: 0},
+				{Name: "ModeFast", Value: 1},
+				{Name: "ModeUnspecified", Value: 0},
+			},
+			expected: `enum Mode {
+  option allow_alias = true;
+  MODE_UNSPECIFIED = 0;
+  MODE_NONE = 0;
+  MODE_FAST = 1;
+}
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			e, err := goinput.FromConsts(tt.typeName, tt.consts...)
+			r.NoError(err)
+			buf := &bytes.Buffer{}
+			r.NoError(e.Render(protogen.NewWriterOutput(buf)))
+			a.Equal(tt.expected+"\n", buf.String())
+		})
+	}
+}
+
+func TestFromConsts_Invalid(t *testing.T) {
+
+	r := require.New(t)
+
+	_, err := goinput.FromConsts("Status", goinput.EnumConst{Name: "StatusActive"}, goinput.EnumConst{Name: "Active", Value: 1})
+	r.EqualError(err, "enum Status: constants StatusActive and Active both become STATUS_ACTIVE")
+
+	_, err = goinput.FromConsts("Status", goinput.EnumConst{Name: "StatusHuge", Value: 1 << 40})
+	r.EqualError(err, "enum Status: constant StatusHuge: value 1099511627776 does not fit into an enum value")
+
+	_, err = goinput.FromConsts("Status", goinput.EnumConst{Name: "StatusUnspecified", Value: 2})
+	r.EqualError(err, "enum Status: constant StatusUnspecified: STATUS_UNSPECIFIED is kept for the zero value")
+}
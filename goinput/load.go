@@ -0,0 +1,525 @@
+package goinput
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/activatedio/protogen/proto"
+)
+
+// basicKinds maps the kinds of Go basic types to proto scalar types.
+var basicKinds = map[types.BasicKind]proto.ScalarType{
+	types.Bool:    proto.Bool,
+	types.Int:     proto.Int64,
+	types.Int8:    proto.Int32,
+	types.Int16:   proto.Int32,
+	types.Int32:   proto.Int32,
+	types.Int64:   proto.Int64,
+	types.Uint:    proto.Uint64,
+	types.Uint8:   proto.Uint32,
+	types.Uint16:  proto.Uint32,
+	types.Uint32:  proto.Uint32,
+	types.Uint64:  proto.Uint64,
+	types.Float32: proto.Float,
+	types.Float64: proto.Double,
+	types.String:  proto.String,
+}
+
+// LoadParams defines optional parameters of Load.
+// PackageName is the proto package of the file, which defaults to the name of the Go package.
+// Path is the path of the proto file, used by other files to import it.
+// Types maps Go types to proto types, and defaults to NewTypeMap.
+type LoadParams struct {
+	PackageName string
+	Path        string
+	Types       TypeMap
+}
+
+// serviceDirective marks the doc comment of an interface type which Load turns into a service.
+const serviceDirective = "//protogen:service"
+
+// loader builds the declarations of a proto file from a type checked Go package.
+type loader struct {
+	fset     *token.FileSet
+	pkg      *types.Package
+	info     *types.Info
+	messages map[*types.TypeName]proto.Message
+	enums    map[*types.TypeName]proto.Enum
+	consts   map[*types.TypeName][]EnumConst
+	types    TypeMap
+}
+
+// Load parses and type checks the Go package in dir, excluding tests, and returns a proto.File for it. Each exported
+// struct type becomes a message and each exported named integer type with constants becomes an enum holding them.
+// Type aliases declare no new type and are left out.
+// Exported interfaces become services when their doc comment holds a //protogen:service directive, so that only the
+// interfaces chosen as services need methods with the RPC shape. Go doc comments become the comments of the
+// declarations. Fields are converted as by Converter.FromStruct, methods as by Converter.FromInterface and constants
+// as by FromConsts.
+func Load(dir string, params ...LoadParams) (proto.File, error) {
+
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	l := &loader{
+		fset: fset,
+		info: &types.Info{
+			Defs: map[*ast.Ident]types.Object{},
+		},
+		messages: map[*types.TypeName]proto.Message{},
+		enums:    map[*types.TypeName]proto.Enum{},
+		consts:   map[*types.TypeName][]EnumConst{},
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	l.pkg, err = conf.Check(bp.ImportPath, fset, files, l.info)
+	if err != nil {
+		return nil, err
+	}
+
+	p := LoadParams{PackageName: bp.Name, Types: NewTypeMap()}
+	for _, _p := range params {
+		if _p.PackageName != "" {
+			p.PackageName = _p.PackageName
+		}
+		p.Path = _p.Path
+		if _p.Types != nil {
+			p.Types = _p.Types
+		}
+	}
+	l.types = p.Types
+
+	return l.file(files, p)
+}
+
+// file builds the proto file from the declarations of the Go files.
+func (l *loader) file(files []*ast.File, p LoadParams) (proto.File, error) {
+
+	f := proto.NewFile(p.PackageName, proto.FileParams{Path: p.Path})
+	comments := map[*types.TypeName]proto.Comments{}
+
+	var structs []*ast.TypeSpec
+	var interfaces []*ast.TypeSpec
+	var enumTypes []*types.TypeName
+	var enums []proto.Enum
+	var messages []proto.Message
+	var services []proto.Service
+
+	for _, file := range files {
+		if file.Doc != nil {
+			f.SetComments(proto.Comments{Leading: commentText(file.Doc)})
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				tn, ok := l.info.Defs[ts.Name].(*types.TypeName)
+				if !ok || !tn.Exported() || tn.IsAlias() || ts.TypeParams != nil {
+					continue
+				}
+				c := proto.Comments{Leading: commentText(docOf(gd, ts.Doc)), Trailing: commentText(ts.Comment)}
+				switch u := tn.Type().Underlying().(type) {
+				case *types.Struct:
+					m := proto.NewMessage(tn.Name()).SetComments(c)
+					l.messages[tn] = m
+					messages = append(messages, m)
+					structs = append(structs, ts)
+				case *types.Interface:
+					if !hasDirective(docOf(gd, ts.Doc), serviceDirective) {
+						continue
+					}
+					interfaces = append(interfaces, ts)
+					services = append(services, proto.NewService(tn.Name()).SetComments(c))
+				case *types.Basic:
+					if u.Info()&types.IsInteger != 0 {
+						enumTypes = append(enumTypes, tn)
+						comments[tn] = c
+					}
+				}
+			}
+		}
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.CONST {
+				l.enumConsts(gd)
+			}
+		}
+	}
+
+	for _, tn := range enumTypes {
+		if len(l.consts[tn]) == 0 {
+			continue
+		}
+		e, err := FromConsts(tn.Name(), l.consts[tn]...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", l.fset.Position(tn.Pos()), err)
+		}
+		l.enums[tn] = e.SetComments(comments[tn])
+		enums = append(enums, e)
+	}
+
+	for _, ts := range structs {
+		if err := l.fields(ts); err != nil {
+			return nil, err
+		}
+	}
+
+	for n, ts := range interfaces {
+		if err := l.methods(ts, services[n]); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.AddEnums(enums...).AddMessages(messages...).AddServices(services...), nil
+}
+
+// enumConsts records the exported constants of a const block by their named integer types.
+func (l *loader) enumConsts(gd *ast.GenDecl) {
+	for _, spec := range gd.Specs {
+		vs := spec.(*ast.ValueSpec)
+		for _, name := range vs.Names {
+			c, ok := l.info.Defs[name].(*types.Const)
+			if !ok || !c.Exported() {
+				continue
+			}
+			named, ok := c.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			v, ok := constant.Int64Val(c.Val())
+			if !ok {
+				continue
+			}
+			l.consts[named.Obj()] = append(l.consts[named.Obj()], EnumConst{
+				Name:  c.Name(),
+				Value: v,
+				Comments: proto.Comments{
+					Leading:  commentText(docOf(gd, vs.Doc)),
+					Trailing: commentText(vs.Comment),
+				},
+			})
+		}
+	}
+}
+
+// fields adds the exported fields of a struct type declaration to its message. The fields are taken from the type,
+// since the declaration may name another struct type, and their comments from the declaration if it is a struct type
+// literal.
+func (l *loader) fields(ts *ast.TypeSpec) error {
+
+	tn := l.info.Defs[ts.Name].(*types.TypeName)
+	m := l.messages[tn]
+	st := tn.Type().Underlying().(*types.Struct)
+
+	var afs []*ast.Field
+	if stt, ok := ts.Type.(*ast.StructType); ok {
+		for _, af := range stt.Fields.List {
+			// embedded fields have no names but are a single field
+			for range max(len(af.Names), 1) {
+				afs = append(afs, af)
+			}
+		}
+	}
+
+	fa := newFieldAdder(m)
+
+	for index := 0; index < st.NumFields(); index++ {
+		v := st.Field(index)
+		if !v.Exported() {
+			continue
+		}
+		var c proto.Comments
+		if index < len(afs) {
+			c = proto.Comments{Leading: commentText(afs[index].Doc), Trailing: commentText(afs[index].Comment)}
+		}
+		if err := l.addField(fa, v, reflect.StructTag(st.Tag(index)), c, index); err != nil {
+			return fmt.Errorf("%s: struct %s: field %s: %w", l.fset.Position(v.Pos()), tn.Name(), v.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// addField adds the proto field for a struct field with its comments, unless its tag skips it.
+func (l *loader) addField(fa *fieldAdder, v *types.Var, st reflect.StructTag, c proto.Comments, index int) error {
+
+	tag, err := parseTag(st)
+	if err != nil {
+		return err
+	}
+	if tag.skip {
+		return nil
+	}
+
+	f, err := l.field(v, tag, index)
+	if err != nil {
+		return err
+	}
+
+	return fa.add(v.Name(), f.SetComments(c), tag)
+}
+
+// methods adds the methods of an interface type declaration to its service. For interface type literals the methods
+// are taken in the order of the declaration, with their comments. Declarations naming another interface type take
+// its methods from the type.
+func (l *loader) methods(ts *ast.TypeSpec, s proto.Service) error {
+
+	tn := l.info.Defs[ts.Name].(*types.TypeName)
+	it := tn.Type().Underlying().(*types.Interface)
+
+	var fns []*types.Func
+	comments := map[*types.Func]proto.Comments{}
+
+	if itt, ok := ts.Type.(*ast.InterfaceType); ok {
+		for _, af := range itt.Methods.List {
+			if len(af.Names) == 0 {
+				return fmt.Errorf("%s: interface %s: embedded interfaces are not supported", l.fset.Position(af.Pos()), tn.Name())
+			}
+			fn := l.info.Defs[af.Names[0]].(*types.Func)
+			fns = append(fns, fn)
+			comments[fn] = proto.Comments{Leading: commentText(af.Doc), Trailing: commentText(af.Comment)}
+		}
+	} else {
+		if it.NumEmbeddeds() > 0 {
+			return fmt.Errorf("%s: interface %s: embedded interfaces are not supported", l.fset.Position(ts.Pos()), tn.Name())
+		}
+		for n := 0; n < it.NumExplicitMethods(); n++ {
+			fns = append(fns, it.ExplicitMethod(n))
+		}
+	}
+
+	for _, fn := range fns {
+		params, err := l.methodParams(fn.Type().(*types.Signature))
+		if err != nil {
+			return fmt.Errorf("%s: interface %s: method %s: %w", l.fset.Position(fn.Pos()), tn.Name(), fn.Name(), err)
+		}
+		s.AddMethods(proto.NewMethod(fn.Name(), params).SetComments(comments[fn]))
+	}
+
+	return nil
+}
+
+// methodParams returns the request and response messages of a method with the RPC shape.
+func (l *loader) methodParams(sig *types.Signature) (proto.MethodParams, error) {
+
+	ps := sig.Params()
+	var in []types.Type
+	for n := 0; n < ps.Len(); n++ {
+		in = append(in, ps.At(n).Type())
+	}
+	if len(in) > 0 && isContext(in[0]) {
+		in = in[1:]
+	}
+
+	rs := sig.Results()
+	if len(in) != 1 || rs.Len() != 2 || !isError(rs.At(1).Type()) || sig.Variadic() {
+		return proto.MethodParams{}, errors.New(rpcShape)
+	}
+
+	req, err := l.message(in[0])
+	if err != nil {
+		return proto.MethodParams{}, fmt.Errorf("request: %w", err)
+	}
+	res, err := l.message(rs.At(0).Type())
+	if err != nil {
+		return proto.MethodParams{}, fmt.Errorf("response: %w", err)
+	}
+
+	return proto.MethodParams{RequestType: req, ResponseType: res}, nil
+}
+
+// message returns the message of a struct type, or of a pointer to a struct type, declared in the package.
+func (l *loader) message(t types.Type) (proto.Message, error) {
+	if p, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := types.Unalias(t).(*types.Named); ok {
+		if m, ok := l.messages[n.Obj()]; ok {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a struct of the package", l.typeString(t))
+}
+
+// isContext reports whether the type is context.Context.
+func isContext(t types.Type) bool {
+	n, ok := types.Unalias(t).(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "context" && n.Obj().Name() == "Context"
+}
+
+// isError reports whether the type is error.
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// field returns the proto field for a struct field with the directives of its tag applied.
+func (l *loader) field(v *types.Var, tag fieldTag, index int) (proto.Field, error) {
+
+	var params proto.FieldParams
+	var err error
+	t := types.Unalias(v.Type())
+
+	switch u := t.(type) {
+	case *types.Pointer:
+		if !l.isMessage(u.Elem()) {
+			params.Label = proto.LabelOptional
+			t = u.Elem()
+		}
+	case *types.Slice:
+		if !isByte(u.Elem()) {
+			params.Label = proto.LabelRepeated
+			t = u.Elem()
+		}
+	case *types.Map:
+		key, ok := l.keyType(u.Key())
+		if !ok {
+			return nil, fmt.Errorf("unsupported map key type %s", l.typeString(u.Key()))
+		}
+		params.KeyType = key
+		t = u.Elem()
+	}
+
+	params.FieldType, err = l.typeRef(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag.field(v.Name(), params, index)
+}
+
+// typeRef returns the proto type of a singular value of the Go type.
+func (l *loader) typeRef(t types.Type) (proto.TypeRef, error) {
+
+	t = types.Unalias(t)
+
+	if p, ok := t.(*types.Pointer); ok && l.isMessage(p.Elem()) {
+		t = p.Elem()
+	}
+
+	if r, ok := l.mapped(t); ok {
+		return r, nil
+	}
+
+	if n, ok := t.(*types.Named); ok {
+		if m, ok := l.messages[n.Obj()]; ok {
+			return m, nil
+		}
+		if e, ok := l.enums[n.Obj()]; ok {
+			return e, nil
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if s, ok := basicKinds[u.Kind()]; ok {
+			return s, nil
+		}
+	case *types.Slice:
+		if isByte(u.Elem()) {
+			return proto.Bytes, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", l.typeString(t))
+}
+
+// mapped returns the proto type the TypeMap holds for a named Go type.
+func (l *loader) mapped(t types.Type) (proto.TypeRef, bool) {
+	n, ok := types.Unalias(t).(*types.Named)
+	if !ok || n.Obj().Pkg() == nil {
+		return nil, false
+	}
+	return lookupType(l.types, n.Obj().Pkg().Path(), n.Obj().Name())
+}
+
+// keyType returns the scalar type of a map key, taken from the TypeMap or from the kind of the Go type. It reports
+// false for types which are not valid proto map keys, such as floating point types.
+func (l *loader) keyType(t types.Type) (proto.ScalarType, bool) {
+	if r, ok := l.mapped(t); ok {
+		s, ok := r.(proto.ScalarType)
+		return s, ok && s.IsMapKey()
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return "", false
+	}
+	s := basicKinds[b.Kind()]
+	return s, s.IsMapKey()
+}
+
+// isMessage reports whether values of the Go type become messages, so that pointers to it need no optional label.
+func (l *loader) isMessage(t types.Type) bool {
+	if r, ok := l.mapped(t); ok {
+		return !isScalar(r)
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+// typeString returns the name of the type, qualifying types of other packages with their package name.
+func (l *loader) typeString(t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(l.pkg))
+}
+
+// isByte reports whether the type is byte.
+func isByte(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Uint8
+}
+
+// docOf returns the doc comment of a spec, or that of its declaration when the declaration holds a single spec.
+func docOf(gd *ast.GenDecl, doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc == nil && len(gd.Specs) == 1 {
+		return gd.Doc
+	}
+	return doc
+}
+
+// hasDirective reports whether the comment group holds the directive on a line of its own.
+func hasDirective(c *ast.CommentGroup, directive string) bool {
+	if c == nil {
+		return false
+	}
+	for _, cm := range c.List {
+		if strings.TrimSpace(cm.Text) == directive {
+			return true
+		}
+	}
+	return false
+}
+
+// commentText returns the text of a comment group without comment markers or the final line break.
+func commentText(c *ast.CommentGroup) string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimSuffix(c.Text(), "\n")
+}
@@ -0,0 +1,152 @@
+package goinput_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/activatedio/protogen/goinput"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	f, err := goinput.Load("testdata/orders", goinput.LoadParams{Path: "orders/orders.proto"})
+	r.NoError(err)
+	a.Equal("orders/orders.proto", f.GetPath())
+	a.Equal("orders", f.GetPackageName())
+
+	buf := &bytes.Buffer{}
+	r.NoError(f.Write(buf))
+	a.Equal(`// Package orders holds the orders domain.
+syntax = "proto3";
+
+package orders;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/duration.proto";
+
+// Status is the state of an order.
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  // StatusActive is an order being processed.
+  STATUS_ACTIVE = 1;
+  STATUS_DONE = 2; // completed
+}
+
+// Order is a customer order.
+message Order {
+  // ID of the order.
+  string id = 1;
+  Status status = 2; // current status
+  repeated LineItem items = 3;
+  map<string, string> notes = 4;
+  int64 total = 10;
+  .google.protobuf.Timestamp created = 7;
+  .google.protobuf.Duration timeout = 8;
+}
+
+// LineItem is a single product in an order.
+message LineItem {
+  string sku = 1;
+  uint32 quantity = 2;
+  sint64 discount = 3 [deprecated = true];
+  oneof adjustment {
+    string coupon_code = 5;
+    string voucher = 6;
+  }
+}
+
+// GetRequest selects an order.
+message GetRequest {
+  string id = 1;
+}
+
+// Orders manages orders.
+service Orders {
+  // Get returns a single order.
+  rpc Get (GetRequest) returns (Order) {
+  }
+  rpc Cancel (GetRequest) returns (Order) { // without context
+  }
+}
+
+`, buf.String())
+}
+
+func TestLoad_Derived(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	f, err := goinput.Load("testdata/derived")
+	r.NoError(err)
+
+	buf := &bytes.Buffer{}
+	r.NoError(f.Write(buf))
+	a.Equal(`// Package derived declares types defined from other types.
+syntax = "proto3";
+
+package derived;
+
+// Base is a struct type literal.
+message Base {
+  // Name of the base.
+  string name = 1;
+  int32 size = 3; // in bytes
+}
+
+// Copy is defined from Base.
+message Copy {
+  string name = 1;
+  int32 size = 3;
+}
+
+// Point is defined from a struct type of another package.
+message Point {
+  int64 x = 1;
+  int64 y = 2;
+}
+
+// API serves bases.
+service API {
+  // Get returns a copy.
+  rpc Get (Base) returns (Copy) {
+  }
+}
+
+// Mirror is defined from API.
+service Mirror {
+  rpc Get (Base) returns (Copy) {
+  }
+}
+
+`, buf.String())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+
+	r := require.New(t)
+
+	_, err := goinput.Load("testdata/invalid")
+	r.EqualError(err, "testdata/invalid/invalid.go:6:2: struct Handler: field Callback: unsupported type func()")
+
+	_, err = goinput.Load("testdata/badrpc")
+	r.EqualError(err, "testdata/badrpc/badrpc.go:10:2: interface Shapes: method Get: "+
+		"signature must be func([context.Context,] *Request) (*Response, error)")
+
+	_, err = goinput.Load("testdata/badtag")
+	r.EqualError(err, `testdata/badtag/badtag.go:5:2: struct Item: field Name: protogen tag: unknown key "nmae"`)
+
+	_, err = goinput.Load("testdata/dupnumber")
+	r.EqualError(err, "testdata/dupnumber/dupnumber.go:7:2: struct Item: field Name: number 3 is already used by field ID")
+
+	_, err = goinput.Load("testdata/badkey")
+	r.EqualError(err, "testdata/badkey/badkey.go:5:2: struct Prices: field ByWeight: unsupported map key type float64")
+
+	_, err = goinput.Load("testdata/missing")
+	r.Error(err)
+}
@@ -0,0 +1,33 @@
+package goinput
+
+import (
+	"strings"
+	"unicode"
+)
+
+// snakeCase converts a Go identifier, such as UserID or HTTPServer, to the lower snake case used for proto field
+// names, such as user_id or http_server.
+func snakeCase(name string) string {
+
+	rs := []rune(name)
+	sb := strings.Builder{}
+
+	for n, r := range rs {
+		if unicode.IsUpper(r) && n > 0 {
+			prevLower := unicode.IsLower(rs[n-1]) || unicode.IsDigit(rs[n-1])
+			nextLower := n+1 < len(rs) && unicode.IsLower(rs[n+1])
+			if prevLower || (unicode.IsUpper(rs[n-1]) && nextLower) {
+				sb.WriteRune('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+
+	return sb.String()
+}
+
+// upperSnakeCase converts a Go identifier, such as StatusActive, to the upper snake case used for enum values, such
+// as STATUS_ACTIVE.
+func upperSnakeCase(name string) string {
+	return strings.ToUpper(snakeCase(name))
+}
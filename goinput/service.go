@@ -0,0 +1,69 @@
+package goinput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/activatedio/protogen/proto"
+)
+
+// rpcShape describes the signature a Go method needs to become a proto method.
+const rpcShape = "signature must be func([context.Context,] *Request) (*Response, error)"
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// FromInterface returns the service for the Go interface type t, with a method for each of its methods in
+// alphabetical order, since reflection does not keep the order they are declared in. Methods
+// must take an optional context.Context and a request struct, and return a response struct and an error. Request
+// and response structs, or pointers to them, become messages as by FromStruct.
+func (c *converter) FromInterface(t reflect.Type) (proto.Service, error) {
+
+	if t.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("type %s: not an interface", t)
+	}
+
+	s := proto.NewService(t.Name())
+
+	for n := 0; n < t.NumMethod(); n++ {
+		m := t.Method(n)
+		params, err := c.methodParams(m.Type)
+		if err != nil {
+			return nil, fmt.Errorf("interface %s: method %s: %w", t.Name(), m.Name, err)
+		}
+		s.AddMethods(proto.NewMethod(m.Name, params))
+	}
+
+	return s, nil
+}
+
+// methodParams returns the request and response messages of a method with the RPC shape.
+func (c *converter) methodParams(ft reflect.Type) (proto.MethodParams, error) {
+
+	var in []reflect.Type
+	for n := 0; n < ft.NumIn(); n++ {
+		in = append(in, ft.In(n))
+	}
+	if len(in) > 0 && in[0] == contextType {
+		in = in[1:]
+	}
+
+	if len(in) != 1 || ft.NumOut() != 2 || ft.Out(1) != errorType || ft.IsVariadic() {
+		return proto.MethodParams{}, errors.New(rpcShape)
+	}
+
+	req, err := c.FromStruct(in[0])
+	if err != nil {
+		return proto.MethodParams{}, fmt.Errorf("request: %w", err)
+	}
+	res, err := c.FromStruct(ft.Out(0))
+	if err != nil {
+		return proto.MethodParams{}, fmt.Errorf("response: %w", err)
+	}
+
+	return proto.MethodParams{RequestType: req, ResponseType: res}, nil
+}
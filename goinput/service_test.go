@@ -0,0 +1,94 @@
+package goinput_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/activatedio/protogen/goinput"
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type GetRequest struct {
+	ID string
+}
+
+type GetResponse struct {
+	Customer *Customer
+}
+
+type Customers interface {
+	Get(ctx context.Context, req *GetRequest) (*GetResponse, error)
+	Find(req GetRequest) (GetResponse, error)
+}
+
+type NoError interface {
+	Get(ctx context.Context, req *GetRequest) *GetResponse
+}
+
+type ScalarRequest interface {
+	Get(ctx context.Context, id string) (*GetResponse, error)
+}
+
+func TestConverter_FromInterface(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	c := goinput.NewConverter()
+	s, err := c.FromInterface(reflect.TypeOf((*Customers)(nil)).Elem())
+	r.NoError(err)
+
+	buf := &bytes.Buffer{}
+	r.NoError(proto.NewFile("unit").AddServices(s).Write(buf))
+	a.Equal(`syntax = "proto3";
+
+package unit;
+
+service Customers {
+  rpc Find (GetRequest) returns (GetResponse) {
+  }
+  rpc Get (GetRequest) returns (GetResponse) {
+  }
+}
+
+`, buf.String())
+	a.Len(c.GetMessages(), 4)
+}
+
+func TestConverter_FromInterface_Invalid(t *testing.T) {
+
+	r := require.New(t)
+
+	cases := []struct {
+		name     string
+		arrange  reflect.Type
+		expected string
+	}{
+		{
+			name:     "not an interface",
+			arrange:  reflect.TypeOf(GetRequest{}),
+			expected: "type goinput_test.GetRequest: not an interface",
+		},
+		{
+			name:     "no error",
+			arrange:  reflect.TypeOf((*NoError)(nil)).Elem(),
+			expected: "interface NoError: method Get: signature must be func([context.Context,] *Request) (*Response, error)",
+		},
+		{
+			name:     "scalar request",
+			arrange:  reflect.TypeOf((*ScalarRequest)(nil)).Elem(),
+			expected: "interface ScalarRequest: method Get: request: type string: not a struct",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			_, err := goinput.NewConverter().FromInterface(tt.arrange)
+			r.EqualError(err, tt.expected)
+		})
+	}
+}
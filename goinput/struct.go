@@ -0,0 +1,203 @@
+package goinput
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/activatedio/protogen/proto"
+)
+
+// scalarKinds maps the kinds of Go scalar types to proto scalar types.
+var scalarKinds = map[reflect.Kind]proto.ScalarType{
+	reflect.Bool:    proto.Bool,
+	reflect.Int:     proto.Int64,
+	reflect.Int8:    proto.Int32,
+	reflect.Int16:   proto.Int32,
+	reflect.Int32:   proto.Int32,
+	reflect.Int64:   proto.Int64,
+	reflect.Uint:    proto.Uint64,
+	reflect.Uint8:   proto.Uint32,
+	reflect.Uint16:  proto.Uint32,
+	reflect.Uint32:  proto.Uint32,
+	reflect.Uint64:  proto.Uint64,
+	reflect.Float32: proto.Float,
+	reflect.Float64: proto.Double,
+	reflect.String:  proto.String,
+}
+
+// Converter builds proto messages from Go struct types and services from Go interface types. Each struct type is
+// converted once, so structs and methods referring to the same struct type share its message.
+type Converter interface {
+	FromStruct(t reflect.Type) (proto.Message, error)
+	FromInterface(t reflect.Type) (proto.Service, error)
+	// GetMessages returns the messages of all converted struct types, including those referenced by fields,
+	// in the order they were converted
+	GetMessages() []proto.Message
+}
+
+// ConverterParams defines optional parameters of NewConverter.
+// Types maps Go types to proto types, and defaults to NewTypeMap.
+type ConverterParams struct {
+	Types TypeMap
+}
+
+// converter holds the messages of the struct types converted so far.
+type converter struct {
+	messages map[reflect.Type]proto.Message
+	order    []proto.Message
+	types    TypeMap
+}
+
+// GetMessages returns the messages of all converted struct types.
+func (c *converter) GetMessages() []proto.Message {
+	return c.order
+}
+
+// FromStruct returns the message for the Go struct type t, which may also be a pointer to a struct.
+// Exported fields become proto fields named in snake case. Types held by the TypeMap of the converter map to their
+// proto types, while other scalars map to the matching scalar types, []byte to
+// bytes, slices to repeated fields, maps to map fields, pointers to scalars to optional fields and structs to
+// references to their own messages. A protogen struct tag adjusts or skips the field, for example
+// protogen:"name=display_name,number=7,optional,oneof=payload,type=sint64,deprecated". The number of a field
+// defaults to the position of the Go field in the struct.
+func (c *converter) FromStruct(t reflect.Type) (proto.Message, error) {
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %s: not a struct", t)
+	}
+	if t.Name() == "" {
+		return nil, fmt.Errorf("type %s: anonymous structs are not supported", t)
+	}
+	if m, ok := c.messages[t]; ok {
+		return m, nil
+	}
+
+	m := proto.NewMessage(t.Name())
+	c.messages[t] = m
+	c.order = append(c.order, m)
+
+	fa := newFieldAdder(m)
+
+	for n := 0; n < t.NumField(); n++ {
+		sf := t.Field(n)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, err := parseTag(sf.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("struct %s: field %s: %w", t.Name(), sf.Name, err)
+		}
+		if tag.skip {
+			continue
+		}
+		f, err := c.field(sf, tag)
+		if err == nil {
+			err = fa.add(sf.Name, f, tag)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("struct %s: field %s: %w", t.Name(), sf.Name, err)
+		}
+	}
+
+	return m, nil
+}
+
+// field returns the proto field for a struct field with the directives of its tag applied.
+func (c *converter) field(sf reflect.StructField, tag fieldTag) (proto.Field, error) {
+
+	var params proto.FieldParams
+	var err error
+	t := sf.Type
+
+	switch {
+	case t.Kind() == reflect.Pointer && !c.isMessage(t.Elem()):
+		params.Label = proto.LabelOptional
+		t = t.Elem()
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		params.Label = proto.LabelRepeated
+		t = t.Elem()
+	case t.Kind() == reflect.Map:
+		key, ok := c.keyType(t.Key())
+		if !ok {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		params.KeyType = key
+		t = t.Elem()
+	}
+
+	params.FieldType, err = c.typeRef(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag.field(sf.Name, params, sf.Index[len(sf.Index)-1])
+}
+
+// typeRef returns the proto type of a singular value of the Go type.
+func (c *converter) typeRef(t reflect.Type) (proto.TypeRef, error) {
+
+	if t.Kind() == reflect.Pointer && c.isMessage(t.Elem()) {
+		t = t.Elem()
+	}
+
+	if r, ok := lookupType(c.types, t.PkgPath(), t.Name()); ok {
+		return r, nil
+	}
+
+	if s, ok := scalarKinds[t.Kind()]; ok {
+		return s, nil
+	}
+
+	switch {
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return proto.Bytes, nil
+	case t.Kind() == reflect.Struct:
+		return c.FromStruct(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// keyType returns the scalar type of a map key, taken from the TypeMap or from the kind of the Go type. It reports
+// false for types which are not valid proto map keys, such as floating point types.
+func (c *converter) keyType(t reflect.Type) (proto.ScalarType, bool) {
+	if r, ok := lookupType(c.types, t.PkgPath(), t.Name()); ok {
+		s, ok := r.(proto.ScalarType)
+		return s, ok && s.IsMapKey()
+	}
+	s, ok := scalarKinds[t.Kind()]
+	return s, ok && s.IsMapKey()
+}
+
+// isMessage reports whether values of the Go type become messages, so that pointers to it need no optional label.
+func (c *converter) isMessage(t reflect.Type) bool {
+	if r, ok := lookupType(c.types, t.PkgPath(), t.Name()); ok {
+		return !isScalar(r)
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// NewConverter creates a new Converter.
+func NewConverter(params ...ConverterParams) Converter {
+
+	p := ConverterParams{Types: NewTypeMap()}
+	for _, _p := range params {
+		if _p.Types != nil {
+			p.Types = _p.Types
+		}
+	}
+
+	return &converter{
+		messages: map[reflect.Type]proto.Message{},
+		types:    p.Types,
+	}
+}
+
+// FromStruct returns the message for the Go struct type t using a new Converter. Use a Converter directly to also
+// obtain the messages of the struct types referenced by its fields.
+func FromStruct(t reflect.Type) (proto.Message, error) {
+	return NewConverter().FromStruct(t)
+}
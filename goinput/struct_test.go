@@ -0,0 +1,297 @@
+package goinput_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/activatedio/protogen/goinput"
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	Street string
+	Lines  []string
+}
+
+type Customer struct {
+	UserID     int64
+	HTTPServer string
+	Nickname   *string
+	Address    Address
+	Previous   []*Address
+	Tags       map[string]int32
+	Avatar     []byte
+	Score      float64 `protogen:"number=10"`
+	Referrer   *Customer
+	internal   bool
+}
+
+type Unsupported struct {
+	Callback func()
+}
+
+type Point struct {
+	X int
+}
+
+type BadMapKey struct {
+	Values map[Point]string
+}
+
+type FloatKey struct {
+	Weights map[float64]string
+}
+
+type StringAsInt struct {
+	Code string `protogen:"type=int64"`
+}
+
+type NarrowedInt struct {
+	Total int64 `protogen:"type=sfixed32"`
+}
+
+type BadTag struct {
+	ID string `protogen:"number=x"`
+}
+
+type UnknownTag struct {
+	ID string `protogen:"number=1,index"`
+}
+
+type OptionalList struct {
+	IDs []string `protogen:"optional"`
+}
+
+type ReusedNumber struct {
+	ID   string
+	Name string `protogen:"number=1"`
+}
+
+type SkippedNumber struct {
+	ID       string `protogen:"number=4"`
+	hidden   bool
+	Checksum []byte `protogen:"skip"`
+	Name     string
+}
+
+type Payment struct {
+	ID       string `protogen:"name=payment_id"`
+	Amount   int64  `protogen:"type=sint64,deprecated"`
+	Card     *Card  `protogen:"oneof=method,number=8"`
+	Account  string `protogen:"oneof=method,number=9"`
+	Memo     string `protogen:"optional"`
+	Checksum []byte `protogen:"skip"`
+	Counts   []int  `protogen:"type=fixed64"`
+}
+
+type Card struct {
+	Number string
+}
+
+func TestConverter_FromStruct(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	c := goinput.NewConverter()
+	m, err := c.FromStruct(reflect.TypeOf(&Customer{}))
+	r.NoError(err)
+	a.Equal("Customer", m.GetName())
+
+	again, err := c.FromStruct(reflect.TypeOf(Customer{}))
+	r.NoError(err)
+	a.Same(m, again)
+
+	buf := &bytes.Buffer{}
+	r.NoError(proto.NewFile("unit").AddMessages(c.GetMessages()...).Write(buf))
+	a.Equal(`syntax = "proto3";
+
+package unit;
+
+message Customer {
+  int64 user_id = 1;
+  string http_server = 2;
+  optional string nickname = 3;
+  Address address = 4;
+  repeated Address previous = 5;
+  map<string, int32> tags = 6;
+  bytes avatar = 7;
+  double score = 10;
+  Customer referrer = 9;
+}
+
+message Address {
+  string street = 1;
+  repeated string lines = 2;
+}
+
+`, buf.String())
+}
+
+func TestConverter_FromStruct_Tags(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	c := goinput.NewConverter()
+	_, err := c.FromStruct(reflect.TypeOf(Payment{}))
+	r.NoError(err)
+
+	buf := &bytes.Buffer{}
+	r.NoError(proto.NewFile("unit").AddMessages(c.GetMessages()...).Write(buf))
+	a.Equal(`syntax = "proto3";
+
+package unit;
+
+message Payment {
+  string payment_id = 1;
+  sint64 amount = 2 [deprecated = true];
+  optional string memo = 5;
+  repeated fixed64 counts = 7;
+  oneof method {
+    Card card = 8;
+    string account = 9;
+  }
+}
+
+message Card {
+  string number = 1;
+}
+
+`, buf.String())
+}
+
+type UUID [16]byte
+
+type Decimal struct {
+	Units int64
+	Nanos int32
+}
+
+type Invoice struct {
+	ID       UUID
+	Total    Decimal
+	Issued   time.Time
+	Paid     *time.Time
+	Terms    *time.Duration
+	Payments map[UUID]*Decimal
+}
+
+func TestConverter_FromStruct_Types(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	c := goinput.NewConverter(goinput.ConverterParams{
+		Types: goinput.NewTypeMap().
+			Add("github.com/activatedio/protogen/goinput_test.UUID", proto.String).
+			Add("github.com/activatedio/protogen/goinput_test.Decimal",
+				proto.NewExternalType("money.Decimal", "money/decimal.proto")),
+	})
+	_, err := c.FromStruct(reflect.TypeOf(Invoice{}))
+	r.NoError(err)
+
+	buf := &bytes.Buffer{}
+	r.NoError(proto.NewFile("unit").AddMessages(c.GetMessages()...).Write(buf))
+	a.Equal(`syntax = "proto3";
+
+package unit;
+
+import "money/decimal.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/duration.proto";
+
+message Invoice {
+  string id = 1;
+  .money.Decimal total = 2;
+  .google.protobuf.Timestamp issued = 3;
+  .google.protobuf.Timestamp paid = 4;
+  .google.protobuf.Duration terms = 5;
+  map<string, .money.Decimal> payments = 6;
+}
+
+`, buf.String())
+}
+
+func TestConverter_FromStruct_Invalid(t *testing.T) {
+
+	r := require.New(t)
+
+	cases := []struct {
+		name     string
+		arrange  reflect.Type
+		expected string
+	}{
+		{
+			name:     "not a struct",
+			arrange:  reflect.TypeOf(""),
+			expected: "type string: not a struct",
+		},
+		{
+			name:     "anonymous struct",
+			arrange:  reflect.TypeOf(struct{ ID string }{}),
+			expected: "type struct { ID string }: anonymous structs are not supported",
+		},
+		{
+			name:     "unsupported type",
+			arrange:  reflect.TypeOf(Unsupported{}),
+			expected: "struct Unsupported: field Callback: unsupported type func()",
+		},
+		{
+			name:     "map key",
+			arrange:  reflect.TypeOf(BadMapKey{}),
+			expected: "struct BadMapKey: field Values: unsupported map key type goinput_test.Point",
+		},
+		{
+			name:     "float map key",
+			arrange:  reflect.TypeOf(FloatKey{}),
+			expected: "struct FloatKey: field Weights: unsupported map key type float64",
+		},
+		{
+			name:     "tag",
+			arrange:  reflect.TypeOf(BadTag{}),
+			expected: `struct BadTag: field ID: protogen tag: invalid number "x"`,
+		},
+		{
+			name:     "unknown tag key",
+			arrange:  reflect.TypeOf(UnknownTag{}),
+			expected: `struct UnknownTag: field ID: protogen tag: unknown key "index"`,
+		},
+		{
+			name:     "type of another kind",
+			arrange:  reflect.TypeOf(StringAsInt{}),
+			expected: "struct StringAsInt: field Code: protogen tag: type int64 cannot hold values of scalar type string",
+		},
+		{
+			name:     "narrower type",
+			arrange:  reflect.TypeOf(NarrowedInt{}),
+			expected: "struct NarrowedInt: field Total: protogen tag: type sfixed32 cannot hold values of scalar type int64",
+		},
+		{
+			name:     "optional repeated",
+			arrange:  reflect.TypeOf(OptionalList{}),
+			expected: "struct OptionalList: field IDs: protogen tag: optional is only allowed on singular fields",
+		},
+		{
+			name:     "reused number",
+			arrange:  reflect.TypeOf(ReusedNumber{}),
+			expected: "struct ReusedNumber: field Name: number 1 is already used by field ID",
+		},
+		{
+			name:     "number of a position after skipped fields",
+			arrange:  reflect.TypeOf(SkippedNumber{}),
+			expected: "struct SkippedNumber: field Name: number 4 is already used by field ID",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			_, err := goinput.FromStruct(tt.arrange)
+			r.EqualError(err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package goinput
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/activatedio/protogen/proto"
+)
+
+// tagName is the key of the struct tag which controls the proto field generated for a Go field.
+const tagName = "protogen"
+
+// integerBits holds the width of the integer scalar types, which the type directive may replace with any integer
+// type at least as wide.
+var integerBits = map[proto.ScalarType]int{
+	proto.Int32:    32,
+	proto.Uint32:   32,
+	proto.Sint32:   32,
+	proto.Fixed32:  32,
+	proto.Sfixed32: 32,
+	proto.Int64:    64,
+	proto.Uint64:   64,
+	proto.Sint64:   64,
+	proto.Fixed64:  64,
+	proto.Sfixed64: 64,
+}
+
+// fitsOverride reports whether the scalar type selected with the type directive holds the values of the scalar type
+// derived from the Go type: integers fit integer types at least as wide, float fits double, and other types only
+// fit themselves.
+func fitsOverride(from, to proto.ScalarType) bool {
+	if from == to {
+		return true
+	}
+	if fb, ok := integerBits[from]; ok {
+		tb, ok := integerBits[to]
+		return ok && tb >= fb
+	}
+	return from == proto.Float && to == proto.Double
+}
+
+// fieldTag holds the directives of a protogen struct tag, such as
+// protogen:"name=display_name,number=7,optional,oneof=payload,type=sint64,deprecated".
+//
+//   - name sets the name of the proto field
+//   - number sets the number of the proto field, which defaults to the position of the Go field in its struct
+//   - skip leaves the Go field out of the message
+//   - optional gives a singular field explicit presence
+//   - oneof places the field in the named oneof of the message
+//   - type replaces the scalar type of the field, or of the elements of a repeated or map field, with a type which
+//     holds its values, such as sint64 or fixed64 for int64
+//   - deprecated sets the deprecated option of the field
+type fieldTag struct {
+	name       string
+	number     int32
+	hasNumber  bool
+	skip       bool
+	optional   bool
+	oneof      string
+	scalarType proto.ScalarType
+	deprecated bool
+}
+
+// parseTag parses the protogen tag of a struct field, returning an error for unknown keys or invalid values.
+func parseTag(st reflect.StructTag) (fieldTag, error) {
+
+	var t fieldTag
+
+	tag, ok := st.Lookup(tagName)
+	if !ok {
+		return t, nil
+	}
+
+	seen := map[string]bool{}
+
+	for _, item := range strings.Split(tag, ",") {
+
+		key, value, hasValue := strings.Cut(strings.TrimSpace(item), "=")
+		if seen[key] {
+			return t, fmt.Errorf("%s tag: key %s is used more than once", tagName, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "name", "number", "oneof", "type":
+			if !hasValue || value == "" {
+				return t, fmt.Errorf("%s tag: key %s needs a value", tagName, key)
+			}
+		case "skip", "optional", "deprecated":
+			if hasValue {
+				return t, fmt.Errorf("%s tag: key %s takes no value", tagName, key)
+			}
+		default:
+			return t, fmt.Errorf("%s tag: unknown key %q", tagName, key)
+		}
+
+		switch key {
+		case "name":
+			t.name = value
+		case "number":
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return t, fmt.Errorf("%s tag: invalid number %q", tagName, value)
+			}
+			t.number, t.hasNumber = int32(n), true
+		case "oneof":
+			t.oneof = value
+		case "type":
+			if !proto.ScalarType(value).Valid() {
+				return t, fmt.Errorf("%s tag: unknown scalar type %q", tagName, value)
+			}
+			t.scalarType = proto.ScalarType(value)
+		case "skip":
+			t.skip = true
+		case "optional":
+			t.optional = true
+		case "deprecated":
+			t.deprecated = true
+		}
+	}
+
+	return t, nil
+}
+
+// apply applies the directives to the parameters derived from the Go type of the field at index in its struct.
+func (t fieldTag) apply(params proto.FieldParams, index int) (proto.FieldParams, error) {
+
+	params.Number = int32(index + 1)
+	if t.hasNumber {
+		params.Number = t.number
+	}
+
+	singular := params.KeyType == "" && params.Label != proto.LabelRepeated
+
+	if t.optional {
+		if !singular {
+			return params, fmt.Errorf("%s tag: optional is only allowed on singular fields", tagName)
+		}
+		params.Label = proto.LabelOptional
+	}
+
+	if t.oneof != "" {
+		if !singular || t.optional {
+			return params, fmt.Errorf("%s tag: oneof fields must be singular without optional", tagName)
+		}
+		params.Label = proto.LabelImplicit
+	}
+
+	if t.scalarType != "" {
+		s, ok := params.FieldType.(proto.ScalarType)
+		if !ok {
+			return params, fmt.Errorf("%s tag: type %s cannot replace message type %s", tagName, t.scalarType,
+				params.FieldType.GetTypeName())
+		}
+		if !fitsOverride(s, t.scalarType) {
+			return params, fmt.Errorf("%s tag: type %s cannot hold values of scalar type %s", tagName, t.scalarType, s)
+		}
+		params.FieldType = t.scalarType
+	}
+
+	return params, nil
+}
+
+// field creates the proto field for the Go field named goName with the directives applied.
+func (t fieldTag) field(goName string, params proto.FieldParams, index int) (proto.Field, error) {
+
+	params, err := t.apply(params, index)
+	if err != nil {
+		return nil, err
+	}
+
+	name := snakeCase(goName)
+	if t.name != "" {
+		name = t.name
+	}
+
+	f := proto.NewField(name, params)
+	if t.deprecated {
+		f.AddOptions(proto.NewOption("deprecated", proto.NewBoolConstant(true)))
+	}
+	return f, nil
+}
+
+// fieldAdder adds fields to a message, placing those with a oneof directive in the named oneof, which is created
+// on first use. It tracks the Go field each number was taken by, since numbers derived from positions and numbers set
+// with the number directive may collide.
+type fieldAdder struct {
+	message proto.Message
+	oneofs  map[string]proto.Oneof
+	numbers map[int32]string
+}
+
+// add adds the field converted from the Go field named goName to the message or to its oneof, returning an error if
+// an earlier field has the same number.
+func (a *fieldAdder) add(goName string, f proto.Field, t fieldTag) error {
+	if other, ok := a.numbers[f.GetNumber()]; ok {
+		return fmt.Errorf("number %d is already used by field %s", f.GetNumber(), other)
+	}
+	a.numbers[f.GetNumber()] = goName
+	if t.oneof == "" {
+		a.message.AddFields(f)
+		return nil
+	}
+	o, ok := a.oneofs[t.oneof]
+	if !ok {
+		o = proto.NewOneof(t.oneof)
+		a.oneofs[t.oneof] = o
+		a.message.AddOneofs(o)
+	}
+	o.AddFields(f)
+	return nil
+}
+
+// newFieldAdder creates a fieldAdder for the message.
+func newFieldAdder(m proto.Message) *fieldAdder {
+	return &fieldAdder{
+		message: m,
+		oneofs:  map[string]proto.Oneof{},
+		numbers: map[int32]string{},
+	}
+}
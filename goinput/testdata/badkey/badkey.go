@@ -0,0 +1,6 @@
+package badkey
+
+// Prices has a map keyed by a floating point type.
+type Prices struct {
+	ByWeight map[float64]string
+}
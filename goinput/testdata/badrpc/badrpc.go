@@ -0,0 +1,11 @@
+package badrpc
+
+// Request is a request.
+type Request struct{}
+
+// Shapes holds a method which does not fit the RPC shape.
+//
+//protogen:service
+type Shapes interface {
+	Get(req *Request) error
+}
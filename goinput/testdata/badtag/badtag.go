@@ -0,0 +1,6 @@
+package badtag
+
+// Item has a tag with an unknown key.
+type Item struct {
+	Name string `protogen:"nmae=title"`
+}
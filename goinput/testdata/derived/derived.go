@@ -0,0 +1,37 @@
+// Package derived declares types defined from other types.
+package derived
+
+import (
+	"context"
+	"image"
+)
+
+// Base is a struct type literal.
+type Base struct {
+	// Name of the base.
+	Name string
+	note string
+	Size int32 // in bytes
+}
+
+// Copy is defined from Base.
+type Copy Base
+
+// Same is an alias of Base, which declares no new type.
+type Same = Base
+
+// Point is defined from a struct type of another package.
+type Point image.Point
+
+// API serves bases.
+//
+//protogen:service
+type API interface {
+	// Get returns a copy.
+	Get(ctx context.Context, req *Base) (*Copy, error)
+}
+
+// Mirror is defined from API.
+//
+//protogen:service
+type Mirror API
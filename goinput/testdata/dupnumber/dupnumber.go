@@ -0,0 +1,8 @@
+package dupnumber
+
+// Item gives ID the number which Name takes from its position.
+type Item struct {
+	ID   string `protogen:"number=3"`
+	note string
+	Name string
+}
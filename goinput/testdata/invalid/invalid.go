@@ -0,0 +1,7 @@
+package invalid
+
+// Handler holds an unsupported field.
+type Handler struct {
+	Name     string
+	Callback func()
+}
@@ -0,0 +1,67 @@
+// Package orders holds the orders domain.
+package orders
+
+import (
+	"context"
+	"time"
+)
+
+// Status is the state of an order.
+type Status int
+
+// Values of Status.
+const (
+	StatusUnknown Status = iota
+	// StatusActive is an order being processed.
+	StatusActive
+	StatusDone // completed
+)
+
+// ID identifies an order.
+type ID string
+
+// Order is a customer order.
+type Order struct {
+	// ID of the order.
+	ID      ID
+	Status  Status // current status
+	Items   []*LineItem
+	Notes   map[string]string
+	Total   int64 `protogen:"number=10"`
+	secret  string
+	Created time.Time
+	Timeout *time.Duration
+}
+
+// LineItem is a single product in an order.
+type LineItem struct {
+	SKU      string
+	Quantity uint32
+	Discount int64  `protogen:"type=sint64,deprecated"`
+	Cached   string `protogen:"skip"`
+	Coupon   string `protogen:"oneof=adjustment,name=coupon_code"`
+	Voucher  string `protogen:"oneof=adjustment"`
+}
+
+type hidden struct {
+	Value int
+}
+
+// GetRequest selects an order.
+type GetRequest struct {
+	ID ID
+}
+
+// Orders manages orders.
+//
+//protogen:service
+type Orders interface {
+	// Get returns a single order.
+	Get(ctx context.Context, req *GetRequest) (*Order, error)
+	Cancel(req GetRequest) (*Order, error) // without context
+}
+
+// Notifier is not a service, so its methods need not fit the RPC shape.
+type Notifier interface {
+	Notify(msg string)
+}
@@ -0,0 +1,56 @@
+package goinput
+
+import (
+	"github.com/activatedio/protogen/proto"
+)
+
+// TypeMap maps Go types to proto types. The Go front ends look up each named Go type in the map before deriving a
+// proto type from its kind, so types such as time.Time can become well-known or custom messages. Go types are keyed
+// by their package path and name, such as "time.Time" or "github.com/google/uuid.UUID".
+type TypeMap interface {
+	// Add maps the Go type to the proto type, replacing any existing mapping. Types created with
+	// proto.NewExternalType and an import path are imported automatically by the files using them.
+	Add(goType string, t proto.TypeRef) TypeMap
+	// Lookup returns the proto type mapped to the Go type
+	Lookup(goType string) (proto.TypeRef, bool)
+}
+
+// typeMap holds the proto types by Go type name.
+type typeMap struct {
+	types map[string]proto.TypeRef
+}
+
+// Add maps the Go type to the proto type.
+func (m *typeMap) Add(goType string, t proto.TypeRef) TypeMap {
+	m.types[goType] = t
+	return m
+}
+
+// Lookup returns the proto type mapped to the Go type.
+func (m *typeMap) Lookup(goType string) (proto.TypeRef, bool) {
+	t, ok := m.types[goType]
+	return t, ok
+}
+
+// NewTypeMap creates a TypeMap holding the default mappings of time.Time to google.protobuf.Timestamp and
+// time.Duration to google.protobuf.Duration.
+func NewTypeMap() TypeMap {
+	return (&typeMap{types: map[string]proto.TypeRef{}}).
+		Add("time.Time", proto.NewExternalType("google.protobuf.Timestamp", "google/protobuf/timestamp.proto")).
+		Add("time.Duration", proto.NewExternalType("google.protobuf.Duration", "google/protobuf/duration.proto"))
+}
+
+// lookupType returns the proto type mapped to the Go type with the package path and name. Unnamed types and
+// predeclared types are never mapped.
+func lookupType(m TypeMap, pkgPath, name string) (proto.TypeRef, bool) {
+	if pkgPath == "" || name == "" {
+		return nil, false
+	}
+	return m.Lookup(pkgPath + "." + name)
+}
+
+// isScalar reports whether the proto type is a scalar type.
+func isScalar(t proto.TypeRef) bool {
+	_, ok := t.(proto.ScalarType)
+	return ok
+}
@@ -0,0 +1,109 @@
+package proto
+
+import (
+	"strings"
+
+	"github.com/activatedio/protogen"
+)
+
+// CommentStyle selects whether comments are written as line comments or block comments.
+type CommentStyle int
+
+// CommentStyleLine writes comments using // on every line.
+// CommentStyleBlock writes comments between /* and */.
+const (
+	CommentStyleLine CommentStyle = iota
+	CommentStyleBlock
+)
+
+// Comments holds the comments attached to an element. Leading comments are written directly above the element,
+// detached comments are written above the leading comment and separated from it by a blank line, and trailing
+// comments are written at the end of the element's first line. Closing comments are written at the end of the body of
+// an element, before its closing brace, or at the end of a file.
+type Comments struct {
+	Leading  string
+	Trailing string
+	Detached []string
+	Closing  []string
+	Style    CommentStyle
+}
+
+// blockStyle reports whether text can be written in the given style as a block comment. Text containing */ would
+// end the block early, so it is written as line comments instead.
+func blockStyle(text string, style CommentStyle) bool {
+	return style == CommentStyleBlock && !strings.Contains(text, "*/")
+}
+
+// commentLines formats text as comment lines in the given style.
+func commentLines(text string, style CommentStyle) []string {
+
+	lines := strings.Split(text, "\n")
+
+	if blockStyle(text, style) {
+		if len(lines) == 1 {
+			return []string{"/* " + lines[0] + " */"}
+		}
+		res := make([]string, 0, len(lines)+2)
+		res = append(res, "/*")
+		for _, l := range lines {
+			res = append(res, strings.TrimRight(" * "+l, " "))
+		}
+		return append(res, " */")
+	}
+
+	res := make([]string, 0, len(lines))
+	for _, l := range lines {
+		res = append(res, strings.TrimRight("// "+l, " "))
+	}
+	return res
+}
+
+// writeLeadingComments writes the detached and leading comments of an element.
+func writeLeadingComments(o protogen.Output, c Comments) error {
+
+	for _, d := range c.Detached {
+		err := o.WriteLines(append(commentLines(d, c.Style), "")...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Leading == "" {
+		return nil
+	}
+
+	return o.WriteLines(commentLines(c.Leading, c.Style)...)
+}
+
+// writeClosingComments writes the closing comments of an element, separated from each other by a blank line.
+func writeClosingComments(o protogen.Output, c Comments) error {
+
+	for n, cc := range c.Closing {
+		lines := commentLines(cc, c.Style)
+		if n > 0 {
+			lines = append([]string{""}, lines...)
+		}
+		err := o.WriteLines(lines...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trailingComment returns the trailing comment of an element formatted to be appended to its first line,
+// or an empty string if there is none. Line breaks in a trailing comment are written as spaces.
+func trailingComment(c Comments) string {
+
+	if c.Trailing == "" {
+		return ""
+	}
+
+	text := strings.Join(strings.Fields(c.Trailing), " ")
+
+	if blockStyle(text, c.Style) {
+		return " /* " + text + " */"
+	}
+	return " // " + text
+}
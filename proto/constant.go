@@ -19,12 +19,16 @@ type Constant interface {
 // constFloat is the third constant in the iota sequence.
 // constInt is the fourth constant in the iota sequence.
 // constMessage is the fifth constant in the iota sequence.
+// constIdentifier is the sixth constant in the iota sequence.
+// constUint is the seventh constant in the iota sequence.
 const (
 	constString = iota
 	constBool
 	constFloat
 	constInt
 	constMessage
+	constIdentifier
+	constUint
 )
 
 // constant represents a flexible type that encapsulates various constant values such as strings, booleans, floats, integers, or messages.
@@ -34,6 +38,7 @@ type constant struct {
 	boolValue    bool
 	floatValue   float64
 	intValue     int
+	uintValue    uint64
 	messageValue tfl.MessageValue
 }
 
@@ -49,8 +54,12 @@ func (c *constant) Render(o protogen.Output) error {
 		return o.Write(strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", c.floatValue), "0"), "."))
 	case constInt:
 		return o.Write(fmt.Sprintf("%d", c.intValue))
+	case constUint:
+		return o.Write(fmt.Sprintf("%d", c.uintValue))
 	case constMessage:
 		return c.messageValue.Render(o)
+	case constIdentifier:
+		return o.Write(c.stringValue)
 	default:
 		return errors.New("unknown constant type")
 	}
@@ -90,6 +99,16 @@ func NewIntConstant(value int) Constant {
 	}
 }
 
+// NewUintConstant creates a new constant of type unsigned integer, for values of uint64 options which do not fit into
+// an int.
+func NewUintConstant(value uint64) Constant {
+
+	return &constant{
+		constType: constUint,
+		uintValue: value,
+	}
+}
+
 // NewMessageValueConstant creates a Constant of type message using the provided tfl.MessageValue.
 func NewMessageValueConstant(value tfl.MessageValue) Constant {
 
@@ -98,3 +117,12 @@ func NewMessageValueConstant(value tfl.MessageValue) Constant {
 		messageValue: value,
 	}
 }
+
+// NewIdentifierConstant creates a Constant which is written as a bare identifier, such as an enum value name.
+func NewIdentifierConstant(value string) Constant {
+
+	return &constant{
+		constType:   constIdentifier,
+		stringValue: value,
+	}
+}
@@ -35,6 +35,16 @@ func TestConstant_Render(t *testing.T) {
 			unit:     NewIntConstant(12345),
 			expected: `12345`,
 		},
+		{
+			name:     "uint",
+			unit:     NewUintConstant(18446744073709551615),
+			expected: `18446744073709551615`,
+		},
+		{
+			name:     "identifier",
+			unit:     NewIdentifierConstant("STATUS_ACTIVE"),
+			expected: `STATUS_ACTIVE`,
+		},
 		{
 			name:     "bool",
 			unit:     NewBoolConstant(true),
@@ -0,0 +1,159 @@
+package proto
+
+import (
+	"fmt"
+
+	"github.com/activatedio/protogen"
+)
+
+// fileContext holds the properties of the file being written which its elements depend on, and collects the
+// imports required by the types they refer to.
+type fileContext struct {
+	file    *file
+	syntax  Syntax
+	imports []Import
+}
+
+// allImports returns the imports of the file merged with the imports required by the types used in it, without
+// changing the file.
+func (c *fileContext) allImports() []Import {
+	return mergeImports(append([]Import(nil), c.file.imports...), c.imports...)
+}
+
+// contextOutput carries the context of the file being written to the elements rendered into it. Elements rendered
+// on their own have no file context.
+type contextOutput struct {
+	protogen.Output
+	ctx *fileContext
+}
+
+// contextOf returns the context of the file being written to the output, or nil if there is none.
+func contextOf(o protogen.Output) *fileContext {
+	if c, ok := o.(*contextOutput); ok {
+		return c.ctx
+	}
+	return nil
+}
+
+// syntaxOf returns the syntax of the file being written to the output, which is proto3 if there is none.
+func syntaxOf(o protogen.Output) Syntax {
+	if ctx := contextOf(o); ctx != nil {
+		return ctx.syntax
+	}
+	return SyntaxProto3
+}
+
+// withContext returns o carrying the file context of parent, if it has one.
+func withContext(o, parent protogen.Output) protogen.Output {
+	if ctx := contextOf(parent); ctx != nil {
+		return &contextOutput{Output: o, ctx: ctx}
+	}
+	return o
+}
+
+// indent returns an Output indenting the lines written to o, which keeps the file context of o.
+func indent(o protogen.Output) protogen.Output {
+	return withContext(protogen.NewIndentingOutput(o, 2), o)
+}
+
+// fileAware is implemented by elements whose validity or rendering depends on the file they are written into.
+// applyFile returns an error if the element uses constructs which are not valid for the syntax of the file.
+type fileAware interface {
+	applyFile(ctx *fileContext) error
+}
+
+// applyElements applies the file context to each element which implements fileAware.
+func applyElements[T any](ctx *fileContext, ts []T) error {
+	for _, t := range ts {
+		if c, ok := any(t).(fileAware); ok {
+			if err := c.applyFile(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// use records the import of the file declaring the type when it is declared elsewhere.
+func (c *fileContext) use(t TypeRef) error {
+	path, err := c.importPath(t)
+	if err != nil {
+		return err
+	}
+	if path != "" && path != c.file.path {
+		c.imports = append(c.imports, NewImport(path))
+	}
+	return nil
+}
+
+// name returns the name used to refer to the type from within the file, while types named in parsed source keep their
+// name as written. Types in other packages are written fully qualified with a leading dot, since proto name
+// resolution would otherwise look the package up relative to the package of the file, so orders.Address used from
+// package acme.orders would refer to acme.orders.Address.
+func (c *fileContext) name(t TypeRef) string {
+
+	switch r := t.(type) {
+	case nil, ScalarType:
+		return typeRefName(t)
+	case *externalType:
+		if r.written != "" {
+			return r.written
+		}
+	}
+
+	pkg := t.GetPackageName()
+	if pkg == "" || pkg == c.file.packageName {
+		return t.GetTypeName()
+	}
+	return "." + pkg + "." + t.GetTypeName()
+}
+
+// importPath returns the path which must be imported to use the type, or an empty string if none is needed.
+// External types without an import path are imported from the file of the project declaring them.
+func (c *fileContext) importPath(t TypeRef) (string, error) {
+	switch r := t.(type) {
+	case *externalType:
+		if r.importPath == "" && c.file.project != nil {
+			if t := c.file.project.lookup(r.fullName); t != nil {
+				return c.importPath(t)
+			}
+		}
+		return r.importPath, nil
+	case declaration:
+		f := r.declaringFile()
+		if f == nil || f == c.file {
+			return "", nil
+		}
+		if f.path == "" {
+			return "", fmt.Errorf("%s is declared in a file without a path", typeRefName(t))
+		}
+		return f.path, nil
+	default:
+		return "", nil
+	}
+}
+
+// declaration is implemented by messages and enums, which record the file they are added to.
+type declaration interface {
+	setFile(*file)
+	declaringFile() *file
+}
+
+// typeUse is a use of a type by an element, whose name depends on the file it is written into.
+type typeUse struct {
+	ref TypeRef
+}
+
+// use records the import the type needs in the file being written.
+func (u typeUse) use(ctx *fileContext) error {
+	return ctx.use(u.ref)
+}
+
+// name returns the name of the type in the file being written to the output, or the name as given when the element
+// is rendered on its own.
+func (u typeUse) name(o protogen.Output) string {
+	if ctx := contextOf(o); ctx != nil {
+		return ctx.name(u.ref)
+	}
+	return typeRefName(u.ref)
+}
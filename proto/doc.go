@@ -1,4 +1,8 @@
 // Package proto contains code to generate proto files
 // It relies on the grammar documented here:
 // https://protobuf.dev/reference/protobuf/proto3-spec/
+// Files may also be written using the proto2 and editions grammars:
+// https://protobuf.dev/reference/protobuf/proto2-spec/
+// https://protobuf.dev/reference/protobuf/edition-2023-spec/
+// Existing proto files can be read into the same model with Parse.
 package proto
@@ -0,0 +1,329 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/activatedio/protogen"
+)
+
+// Enum represents a renderable enum declaration which holds values, options and reserved statements.
+type Enum interface {
+	protogen.Renderer
+	GetName() string
+	// GetTypeName returns the dotted name of the enum within its package, such as Order.Status for nested enums
+	GetTypeName() string
+	SetPackageName(string) Enum
+	GetPackageName() string
+	SetComments(Comments) Enum
+	GetValues() []EnumValue
+	AddValues(...EnumValue) Enum
+	AddOptions(...Option) Enum
+	AddReserved(...Reserved) Enum
+}
+
+// enum represents a named enum declaration.
+type enum struct {
+	name        string
+	packageName string
+	parent      Message
+	file        *file
+	comments    Comments
+	values      []EnumValue
+	options     []Option
+	reserved    []Reserved
+}
+
+// GetName returns the name of the enum.
+func (e *enum) GetName() string {
+	return e.name
+}
+
+// GetTypeName returns the dotted name of the enum within its package.
+func (e *enum) GetTypeName() string {
+	return typeName(e.parent, e.name)
+}
+
+// SetPackageName sets the package the enum is declared in and returns the updated Enum.
+func (e *enum) SetPackageName(s string) Enum {
+	e.packageName = s
+	return e
+}
+
+// GetPackageName returns the package of the enum. Nested enums share the package of their parent, and enums without
+// a package name take the package of the file declaring them.
+func (e *enum) GetPackageName() string {
+	if e.parent != nil {
+		return e.parent.GetPackageName()
+	}
+	if e.packageName == "" && e.file != nil {
+		return e.file.packageName
+	}
+	return e.packageName
+}
+
+func (e *enum) setFile(f *file) {
+	e.file = f
+}
+
+// declaringFile returns the file the enum is declared in. Nested enums are declared in the file of their parent.
+func (e *enum) declaringFile() *file {
+	if d, ok := e.parent.(declaration); ok {
+		return d.declaringFile()
+	}
+	return e.file
+}
+
+func (e *enum) setParent(p Message) {
+	e.parent = p
+}
+
+// SetComments sets the comments written with the enum and returns the updated Enum.
+func (e *enum) SetComments(c Comments) Enum {
+	e.comments = c
+	return e
+}
+
+// GetValues returns the values of the enum.
+func (e *enum) GetValues() []EnumValue {
+	return e.values
+}
+
+// AddValues appends one or more EnumValue instances to the enum and returns the updated Enum.
+func (e *enum) AddValues(v ...EnumValue) Enum {
+	e.values = append(e.values, v...)
+	return e
+}
+
+// AddOptions appends one or more Option instances to the enum, such as allow_alias, and returns the updated Enum.
+func (e *enum) AddOptions(o ...Option) Enum {
+	e.options = append(e.options, o...)
+	return e
+}
+
+// AddReserved appends one or more Reserved statements to the enum and returns the updated Enum.
+func (e *enum) AddReserved(r ...Reserved) Enum {
+	e.reserved = append(e.reserved, r...)
+	return e
+}
+
+// allowsAlias reports whether the enum has the allow_alias option set to true.
+func (e *enum) allowsAlias() bool {
+	for _, opt := range e.options {
+		if opt.GetName() != "allow_alias" {
+			continue
+		}
+		v, err := renderToString(opt.GetValue())
+		if err == nil && v == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// valueErrors returns an error for each value which uses a reserved name or number, or which shares a number with
+// an earlier value when the enum does not allow aliases.
+func (e *enum) valueErrors() []error {
+
+	var errs []error
+
+	alias := e.allowsAlias()
+	numbers := map[int32]string{}
+
+	for _, v := range e.values {
+		if err := checkReserved(e.reserved, "value", v.GetName(), v.GetNumber()); err != nil {
+			errs = append(errs, err)
+		}
+		if alias {
+			continue
+		}
+		if other, ok := numbers[v.GetNumber()]; ok {
+			errs = append(errs, fmt.Errorf("value %s reuses number %d of value %s without allow_alias",
+				v.GetName(), v.GetNumber(), other))
+		} else {
+			numbers[v.GetNumber()] = v.GetName()
+		}
+	}
+
+	return errs
+}
+
+// checkValues returns an error for the first value which uses a reserved name or number or reuses a number.
+func (e *enum) checkValues() error {
+	if errs := e.valueErrors(); len(errs) > 0 {
+		return fmt.Errorf("enum %s: %w", e.name, errs[0])
+	}
+	return nil
+}
+
+// syntaxError returns an error if the options of the enum are not valid for the syntax. In proto3 the first value
+// must be zero.
+func (e *enum) syntaxError(s Syntax) error {
+	if err := checkFeatures(s, targetEnum, e.options); err != nil {
+		return err
+	}
+	if s == SyntaxProto3 && len(e.values) > 0 && e.values[0].GetNumber() != 0 {
+		return fmt.Errorf("the first value must be zero in %s", s)
+	}
+	return nil
+}
+
+// applyFile returns an error if the enum, its options or its values are not valid for the syntax.
+func (e *enum) applyFile(ctx *fileContext) error {
+
+	err := e.syntaxError(ctx.syntax)
+	for _, es := range [][]any{toAny(e.values), toAny(e.reserved)} {
+		if err == nil {
+			err = applyElements(ctx, es)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("enum %s: %w", e.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the enum and its values.
+func (e *enum) validate(v *validator) {
+	v.within("enum", e.name, func() {
+		v.report(checkIdent(e.name))
+		if len(e.values) == 0 {
+			v.report(errors.New("enums must have at least one value"))
+		}
+		v.reportAll(e.valueErrors())
+		v.report(e.syntaxError(v.syntax))
+		validateElements(v, e.reserved)
+		validateElements(v, e.values)
+	})
+}
+
+// Render writes the enum declaration, including its options, reserved statements and values, to the provided Output.
+func (e *enum) Render(o protogen.Output) error {
+
+	err := e.checkValues()
+	if err != nil {
+		return err
+	}
+
+	err = writeLeadingComments(o, e.comments)
+	if err != nil {
+		return err
+	}
+
+	err = o.WriteLines(fmt.Sprintf("enum %s {%s", e.name, trailingComment(e.comments)))
+	if err != nil {
+		return err
+	}
+
+	io := indent(o)
+
+	for _, r := range [][]protogen.Renderer{toRenderers(e.options), toRenderers(e.reserved), toRenderers(e.values)} {
+		err = renderElements(io, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writeClosingComments(io, e.comments)
+	if err != nil {
+		return err
+	}
+
+	return o.WriteLines("}", "")
+}
+
+// NewEnum creates a new Enum instance with the specified name.
+func NewEnum(name string) Enum {
+	return &enum{
+		name: name,
+	}
+}
+
+// EnumValue represents a single named value within an enum.
+type EnumValue interface {
+	protogen.Renderer
+	GetName() string
+	GetNumber() int32
+	SetComments(Comments) EnumValue
+	AddOptions(...Option) EnumValue
+}
+
+// enumValue represents a named enum value with its number and options.
+type enumValue struct {
+	name     string
+	number   int32
+	comments Comments
+	options  []Option
+}
+
+// GetName returns the name of the enum value.
+func (v *enumValue) GetName() string {
+	return v.name
+}
+
+// GetNumber returns the number of the enum value.
+func (v *enumValue) GetNumber() int32 {
+	return v.number
+}
+
+// SetComments sets the comments written with the enum value and returns the updated EnumValue.
+func (v *enumValue) SetComments(c Comments) EnumValue {
+	v.comments = c
+	return v
+}
+
+// AddOptions appends one or more Option instances to the enum value and returns the updated EnumValue.
+func (v *enumValue) AddOptions(o ...Option) EnumValue {
+	v.options = append(v.options, o...)
+	return v
+}
+
+// applyFile returns an error if the options of the enum value are not valid for the syntax.
+func (v *enumValue) applyFile(ctx *fileContext) error {
+	err := checkFeatures(ctx.syntax, targetEnumValue, v.options)
+	if err != nil {
+		return fmt.Errorf("value %s: %w", v.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the name and options of the enum value.
+func (v *enumValue) validate(vr *validator) {
+	vr.within("value", v.name, func() {
+		vr.report(checkIdent(v.name))
+		vr.report(checkFeatures(vr.syntax, targetEnumValue, v.options))
+	})
+}
+
+// Render writes the enum value, including any options in bracketed form, to the provided Output.
+func (v *enumValue) Render(o protogen.Output) error {
+
+	opts, err := compactOptions(v.options)
+	if err != nil {
+		return err
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(v.name)
+	sb.WriteString(" = ")
+	sb.WriteString(fmt.Sprintf("%d", v.number))
+	sb.WriteString(opts)
+	sb.WriteString(";")
+	sb.WriteString(trailingComment(v.comments))
+
+	err = writeLeadingComments(o, v.comments)
+	if err != nil {
+		return err
+	}
+
+	return o.WriteLines(sb.String())
+}
+
+// NewEnumValue creates a new EnumValue with the specified name and number.
+func NewEnumValue(name string, number int32) EnumValue {
+	return &enumValue{
+		name:   name,
+		number: number,
+	}
+}
@@ -0,0 +1,96 @@
+package proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/activatedio/protogen"
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnum_Render(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	cases := []struct {
+		name    string
+		arrange func() proto.Enum
+		assert  func(got []byte, err error)
+	}{
+		{
+			name: "simple",
+			arrange: func() proto.Enum {
+				return proto.NewEnum("Status").AddValues(
+					proto.NewEnumValue("STATUS_UNSPECIFIED", 0),
+					proto.NewEnumValue("STATUS_ACTIVE", 1),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "full",
+			arrange: func() proto.Enum {
+				return proto.NewEnum("Kind").AddOptions(
+					proto.NewOption("allow_alias", proto.NewBoolConstant(true)),
+				).AddReserved(
+					proto.NewReservedNumbers(2, 15),
+					proto.NewReservedRange(9, 11),
+					proto.NewReservedNames("KIND_OLD"),
+				).AddValues(
+					proto.NewEnumValue("KIND_UNSPECIFIED", 0),
+					proto.NewEnumValue("KIND_FIRST", 1),
+					proto.NewEnumValue("KIND_PRIMARY", 1).AddOptions(
+						proto.NewOption("deprecated", proto.NewBoolConstant(true)),
+						proto.NewOption("api.label", proto.NewStringConstant("primary")),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`enum Kind {
+  option allow_alias = true;
+  reserved 2, 15;
+  reserved 9 to 11;
+  reserved "KIND_OLD";
+  KIND_UNSPECIFIED = 0;
+  KIND_FIRST = 1;
+  KIND_PRIMARY = 1 [deprecated = true, (api.label) = "primary"];
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "alias without allow_alias",
+			arrange: func() proto.Enum {
+				return proto.NewEnum("Kind").AddValues(
+					proto.NewEnumValue("KIND_FIRST", 0),
+					proto.NewEnumValue("KIND_PRIMARY", 0),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "enum Kind: value KIND_PRIMARY reuses number 0 of value KIND_FIRST without allow_alias")
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			buf := &bytes.Buffer{}
+			unit := tt.arrange()
+			err := unit.Render(protogen.NewWriterOutput(buf))
+			tt.assert(buf.Bytes(), err)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/activatedio/protogen"
+)
+
+// Extend represents an extend block which adds extension fields to a target message, such as
+// google.protobuf.MethodOptions when defining custom options.
+type Extend interface {
+	protogen.Renderer
+	GetTarget() TypeRef
+	SetComments(Comments) Extend
+	AddFields(...Field) Extend
+}
+
+// extend represents an extend block with its target message and extension fields.
+type extend struct {
+	target   typeUse
+	comments Comments
+	fields   []Field
+}
+
+// GetTarget returns the message being extended.
+func (e *extend) GetTarget() TypeRef {
+	return e.target.ref
+}
+
+// SetComments sets the comments written with the extend block and returns the updated Extend.
+func (e *extend) SetComments(c Comments) Extend {
+	e.comments = c
+	return e
+}
+
+// AddFields appends one or more extension fields to the extend block and returns the updated Extend.
+func (e *extend) AddFields(f ...Field) Extend {
+	e.fields = append(e.fields, f...)
+	return e
+}
+
+// fieldErrors returns an error for each extension field which is a map or shares a name or number with an earlier
+// extension field.
+func (e *extend) fieldErrors() []error {
+
+	var errs []error
+
+	for _, f := range e.fields {
+		if m, ok := f.(*field); ok && m.keyType != "" {
+			errs = append(errs, fmt.Errorf("field %s: extension fields cannot be maps", f.GetName()))
+		}
+	}
+
+	return append(errs, fieldErrors(e.fields, nil, nil)...)
+}
+
+// checkFields returns an error if the target is not a message, or for the first problem with the extension fields.
+func (e *extend) checkFields() error {
+
+	err := checkMessageRef(e.target.ref)
+	if err != nil {
+		return fmt.Errorf("extend: %w", err)
+	}
+
+	if errs := e.fieldErrors(); len(errs) > 0 {
+		return fmt.Errorf("extend %s: %w", typeRefName(e.target.ref), errs[0])
+	}
+
+	return nil
+}
+
+// syntaxError returns an error if the extend block is not valid for the syntax. In proto3 only the options messages
+// of google/protobuf/descriptor.proto may be extended.
+func (e *extend) syntaxError(s Syntax) error {
+	if s == SyntaxProto3 && !isOptionsMessage(e.target.ref) {
+		return fmt.Errorf("only custom options may be defined in %s", s)
+	}
+	return checkLabelsRequired(s, e.fields)
+}
+
+// validate reports every problem with the target and fields of the extend block.
+func (e *extend) validate(v *validator) {
+	v.within("extend", typeRefName(e.target.ref), func() {
+		v.report(checkMessageRef(e.target.ref))
+		v.reportAll(e.fieldErrors())
+		v.report(e.syntaxError(v.syntax))
+		validateElements(v, e.fields)
+	})
+}
+
+// applyFile returns an error if the extend block or its fields are not valid for the syntax.
+// It also records the import the target message needs.
+func (e *extend) applyFile(ctx *fileContext) error {
+
+	err := e.syntaxError(ctx.syntax)
+	if err == nil {
+		err = e.target.use(ctx)
+	}
+	if err == nil {
+		err = applyElements(ctx, e.fields)
+	}
+	if err != nil {
+		return fmt.Errorf("extend %s: %w", typeRefName(e.target.ref), err)
+	}
+	return nil
+}
+
+// isOptionsMessage reports whether the type is one of the options messages of google/protobuf/descriptor.proto.
+func isOptionsMessage(t TypeRef) bool {
+	return t != nil && t.GetPackageName() == "google.protobuf" && strings.HasSuffix(t.GetTypeName(), "Options")
+}
+
+// Render writes the extend block and its fields to the provided Output.
+func (e *extend) Render(o protogen.Output) error {
+
+	err := e.checkFields()
+	if err != nil {
+		return err
+	}
+
+	err = writeLeadingComments(o, e.comments)
+	if err != nil {
+		return err
+	}
+
+	err = o.WriteLines(fmt.Sprintf("extend %s {%s", e.target.name(o), trailingComment(e.comments)))
+	if err != nil {
+		return err
+	}
+
+	io := newBlockOutput(o)
+
+	err = renderElements(io, toRenderers(e.fields))
+	if err != nil {
+		return err
+	}
+
+	err = writeClosingComments(io, e.comments)
+	if err != nil {
+		return err
+	}
+
+	return o.WriteLines("}", "")
+}
+
+// NewExtend creates a new Extend block for the specified target message.
+func NewExtend(target TypeRef) Extend {
+	return &extend{
+		target: typeUse{ref: target},
+	}
+}
@@ -0,0 +1,43 @@
+package proto
+
+import (
+	"fmt"
+
+	"github.com/activatedio/protogen"
+)
+
+// ExtensionRange represents an extensions statement which declares field numbers available to extensions of a
+// proto2 message.
+type ExtensionRange interface {
+	protogen.Renderer
+	IncludesNumber(int32) bool
+}
+
+// extensionRange is an inclusive range of extension numbers.
+type extensionRange struct {
+	numberRange
+}
+
+// IncludesNumber reports whether the number falls within the extension range.
+func (e *extensionRange) IncludesNumber(n int32) bool {
+	return e.includes(n)
+}
+
+// Render writes the extensions statement to the provided Output.
+func (e *extensionRange) Render(o protogen.Output) error {
+	return o.WriteLines(fmt.Sprintf("extensions %s;", e.numberRange))
+}
+
+// NewExtensionRange creates an ExtensionRange for the inclusive range of numbers from start to end.
+func NewExtensionRange(start, end int32) ExtensionRange {
+	return &extensionRange{
+		numberRange: numberRange{start: start, end: end},
+	}
+}
+
+// NewExtensionRangeToMax creates an ExtensionRange for all numbers from start up to the maximum field number.
+func NewExtensionRangeToMax(start int32) ExtensionRange {
+	return &extensionRange{
+		numberRange: numberRange{start: start, toMax: true},
+	}
+}
@@ -0,0 +1,188 @@
+package proto
+
+import "fmt"
+
+// featuresPrefix is the prefix of the names of editions feature options.
+const featuresPrefix = "features."
+
+// featureTarget identifies the kind of element an option is set on.
+type featureTarget int
+
+const (
+	targetFile featureTarget = iota
+	targetMessage
+	targetField
+	targetOneof
+	targetEnum
+	targetEnumValue
+	targetService
+	targetMethod
+)
+
+// String returns the name of the element kind used in error messages.
+func (t featureTarget) String() string {
+	switch t {
+	case targetFile:
+		return "file"
+	case targetMessage:
+		return "message"
+	case targetField:
+		return "field"
+	case targetOneof:
+		return "oneof"
+	case targetEnum:
+		return "enum"
+	case targetEnumValue:
+		return "enum value"
+	case targetService:
+		return "service"
+	default:
+		return "method"
+	}
+}
+
+// FieldPresence is the value of the field_presence feature.
+type FieldPresence string
+
+// Values of the field_presence feature.
+const (
+	FieldPresenceExplicit       FieldPresence = "EXPLICIT"
+	FieldPresenceImplicit       FieldPresence = "IMPLICIT"
+	FieldPresenceLegacyRequired FieldPresence = "LEGACY_REQUIRED"
+)
+
+// EnumType is the value of the enum_type feature.
+type EnumType string
+
+// Values of the enum_type feature.
+const (
+	EnumTypeOpen   EnumType = "OPEN"
+	EnumTypeClosed EnumType = "CLOSED"
+)
+
+// RepeatedFieldEncoding is the value of the repeated_field_encoding feature.
+type RepeatedFieldEncoding string
+
+// Values of the repeated_field_encoding feature.
+const (
+	RepeatedFieldEncodingPacked   RepeatedFieldEncoding = "PACKED"
+	RepeatedFieldEncodingExpanded RepeatedFieldEncoding = "EXPANDED"
+)
+
+// UTF8Validation is the value of the utf8_validation feature.
+type UTF8Validation string
+
+// Values of the utf8_validation feature.
+const (
+	UTF8ValidationVerify UTF8Validation = "VERIFY"
+	UTF8ValidationNone   UTF8Validation = "NONE"
+)
+
+// MessageEncoding is the value of the message_encoding feature.
+type MessageEncoding string
+
+// Values of the message_encoding feature.
+const (
+	MessageEncodingLengthPrefixed MessageEncoding = "LENGTH_PREFIXED"
+	MessageEncodingDelimited      MessageEncoding = "DELIMITED"
+)
+
+// JSONFormat is the value of the json_format feature.
+type JSONFormat string
+
+// Values of the json_format feature.
+const (
+	JSONFormatAllow            JSONFormat = "ALLOW"
+	JSONFormatLegacyBestEffort JSONFormat = "LEGACY_BEST_EFFORT"
+)
+
+// feature is an option which sets an editions feature. It records the kinds of elements the feature may be set on.
+type feature struct {
+	option
+	targets []featureTarget
+}
+
+// SetComments sets the comments written with the feature option and returns the updated Option.
+func (f *feature) SetComments(c Comments) Option {
+	f.comments = c
+	return f
+}
+
+// appliesTo reports whether the feature may be set on the kind of element.
+func (f *feature) appliesTo(target featureTarget) bool {
+	for _, t := range f.targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// featureTargets holds the kinds of elements each feature may be set on.
+var featureTargets = map[string][]featureTarget{
+	"field_presence":          {targetFile, targetField},
+	"enum_type":               {targetFile, targetEnum},
+	"repeated_field_encoding": {targetFile, targetField},
+	"utf8_validation":         {targetFile, targetField},
+	"message_encoding":        {targetFile, targetField},
+	"json_format":             {targetFile, targetMessage, targetEnum},
+}
+
+// newFeature creates a feature option with an enum value.
+func newFeature(name, value string) Option {
+	return &feature{
+		option: option{
+			name:          featuresPrefix + name,
+			constantValue: NewIdentifierConstant(value),
+		},
+		targets: featureTargets[name],
+	}
+}
+
+// NewFieldPresenceFeature creates the field_presence feature option, which may be set on files and fields.
+func NewFieldPresenceFeature(v FieldPresence) Option {
+	return newFeature("field_presence", string(v))
+}
+
+// NewEnumTypeFeature creates the enum_type feature option, which may be set on files and enums.
+func NewEnumTypeFeature(v EnumType) Option {
+	return newFeature("enum_type", string(v))
+}
+
+// NewRepeatedFieldEncodingFeature creates the repeated_field_encoding feature option, which may be set on files and fields.
+func NewRepeatedFieldEncodingFeature(v RepeatedFieldEncoding) Option {
+	return newFeature("repeated_field_encoding", string(v))
+}
+
+// NewUTF8ValidationFeature creates the utf8_validation feature option, which may be set on files and fields.
+func NewUTF8ValidationFeature(v UTF8Validation) Option {
+	return newFeature("utf8_validation", string(v))
+}
+
+// NewMessageEncodingFeature creates the message_encoding feature option, which may be set on files and fields.
+func NewMessageEncodingFeature(v MessageEncoding) Option {
+	return newFeature("message_encoding", string(v))
+}
+
+// NewJSONFormatFeature creates the json_format feature option, which may be set on files, messages and enums.
+func NewJSONFormatFeature(v JSONFormat) Option {
+	return newFeature("json_format", string(v))
+}
+
+// checkFeatures returns an error if a feature option is used outside editions or set on a kind of element
+// it does not apply to.
+func checkFeatures(s Syntax, target featureTarget, opts []Option) error {
+	for _, o := range opts {
+		f, ok := o.(*feature)
+		if !ok {
+			continue
+		}
+		if s != SyntaxEditions {
+			return fmt.Errorf("option %s: features are only allowed in editions", f.name)
+		}
+		if !f.appliesTo(target) {
+			return fmt.Errorf("option %s: cannot be set on a %s", f.name, target)
+		}
+	}
+	return nil
+}
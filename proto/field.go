@@ -1,61 +1,272 @@
 package proto
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/activatedio/protogen"
 )
 
-// FieldParams defines parameters for a field in a proto message, including its type, number, and whether it is repeated.
+// FieldLabel describes the cardinality and presence of a field.
+type FieldLabel int
+
+// LabelImplicit is a singular field without a label, which has implicit presence in proto3.
+// LabelOptional is a singular field with explicit presence.
+// LabelRepeated is a field holding any number of values.
+// LabelRequired is a proto2 field which must be set.
+const (
+	LabelImplicit FieldLabel = iota
+	LabelOptional
+	LabelRepeated
+	LabelRequired
+)
+
+// String returns the keyword of the label, which is empty for LabelImplicit.
+func (l FieldLabel) String() string {
+	switch l {
+	case LabelOptional:
+		return "optional"
+	case LabelRepeated:
+		return "repeated"
+	case LabelRequired:
+		return "required"
+	default:
+		return ""
+	}
+}
+
+// FieldParams defines parameters for a field in a proto message, including its type, number, and label.
+// Repeated is equivalent to setting Label to LabelRepeated and cannot be combined with another label.
+// Setting KeyType makes the field a map from KeyType to FieldType. Default sets the proto2 default value of the field.
 type FieldParams struct {
-	FieldType     string
+	FieldType     TypeRef
+	KeyType       ScalarType
 	Number        int32
+	Label         FieldLabel
 	Repeated      bool
+	Default       Constant
 	InlineComment string
 }
 
 // Field represents an interface that extends Renderer for defining a structured field in a message or schema.
 type Field interface {
 	protogen.Renderer
+	GetName() string
+	GetNumber() int32
+	GetLabel() FieldLabel
+	SetComments(Comments) Field
+	AddOptions(...Option) Field
+}
+
+// mapKeyTypes holds the scalar types which may be used as map keys: any integral or string type.
+var mapKeyTypes = map[ScalarType]bool{
+	Int32:    true,
+	Int64:    true,
+	Uint32:   true,
+	Uint64:   true,
+	Sint32:   true,
+	Sint64:   true,
+	Fixed32:  true,
+	Fixed64:  true,
+	Sfixed32: true,
+	Sfixed64: true,
+	Bool:     true,
+	String:   true,
 }
 
-// field represents a field with a name, type, unique number, and a flag indicating if it is repeated.
+// IsMapKey reports whether the scalar type may be used as the key type of a map field. Floating point and bytes
+// types may not.
+func (s ScalarType) IsMapKey() bool {
+	return mapKeyTypes[s]
+}
+
+// field represents a field with a name, type, unique number, and label.
 type field struct {
 	name          string
-	fieldType     string
+	fieldType     typeUse
+	keyType       ScalarType
 	number        int32
+	label         FieldLabel
 	repeated      bool
+	defaultValue  Constant
 	inlineComment string
+	comments      Comments
+	options       []Option
+}
+
+// GetName returns the name of the field.
+func (f *field) GetName() string {
+	return f.name
+}
+
+// GetNumber returns the number of the field.
+func (f *field) GetNumber() int32 {
+	return f.number
+}
+
+// GetLabel returns the label of the field, which is LabelRepeated for fields created with Repeated set.
+func (f *field) GetLabel() FieldLabel {
+	if f.repeated {
+		return LabelRepeated
+	}
+	return f.label
+}
+
+// SetComments sets the comments written with the field and returns the updated Field.
+// A trailing comment takes the place of the InlineComment given in FieldParams.
+func (f *field) SetComments(c Comments) Field {
+	f.comments = c
+	return f
+}
+
+// AddOptions appends one or more Option instances to the field and returns the updated Field.
+// Options are rendered in bracketed form after the field number.
+func (f *field) AddOptions(o ...Option) Field {
+	f.options = append(f.options, o...)
+	return f
+}
+
+// checkLabel returns an error if the field combines Repeated with another label.
+func (f *field) checkLabel() error {
+	if f.repeated && f.label != LabelImplicit && f.label != LabelRepeated {
+		return fmt.Errorf("labels %s and repeated are mutually exclusive", f.label)
+	}
+	return nil
+}
+
+// syntaxError returns an error if the field uses constructs which are not valid for the syntax: required fields
+// and default values are proto2 only, and editions express labels other than repeated through features.
+func (f *field) syntaxError(s Syntax) error {
+	if err := checkFeatures(s, targetField, f.options); err != nil {
+		return err
+	}
+	switch {
+	case s == SyntaxProto3 && f.GetLabel() == LabelRequired:
+		return fmt.Errorf("required fields are not allowed in %s", s)
+	case s == SyntaxProto3 && f.defaultValue != nil:
+		return fmt.Errorf("default values are not allowed in %s", s)
+	case s == SyntaxEditions && (f.GetLabel() == LabelOptional || f.GetLabel() == LabelRequired):
+		return fmt.Errorf("%s fields are not allowed in %s, use features.field_presence", f.GetLabel(), s)
+	default:
+		return nil
+	}
+}
+
+// applyFile returns an error if the field is not valid for the syntax and records the import its type needs.
+func (f *field) applyFile(ctx *fileContext) error {
+
+	err := f.syntaxError(ctx.syntax)
+	if err == nil {
+		err = f.fieldType.use(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("field %s: %w", f.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the name, number, type, label and syntax of the field.
+func (f *field) validate(v *validator) {
+	v.within("field", f.name, func() {
+		v.report(checkIdent(f.name))
+		v.report(checkFieldNumber(f.number))
+		v.report(checkTypeRef(f.fieldType.ref))
+		v.report(f.checkLabel())
+		v.report(f.checkMap())
+		v.report(f.syntaxError(v.syntax))
+	})
+}
+
+// allOptions returns the options of the field, preceded by the default value when one is set.
+func (f *field) allOptions() []Option {
+	if f.defaultValue == nil {
+		return f.options
+	}
+	return append([]Option{NewOption("default", f.defaultValue)}, f.options...)
+}
+
+// checkMap returns an error if the field is a map with an invalid key type or has a label.
+func (f *field) checkMap() error {
+	if f.keyType == "" {
+		return nil
+	}
+	if !mapKeyTypes[f.keyType] {
+		return fmt.Errorf("map key type %s must be an integral or string type", f.keyType)
+	}
+	switch f.GetLabel() {
+	case LabelImplicit:
+		return nil
+	case LabelRepeated:
+		return errors.New("map fields cannot be repeated")
+	default:
+		return fmt.Errorf("map fields cannot be %s", f.GetLabel())
+	}
 }
 
 // Render formats the field as a string in protocol buffer syntax and writes it to the provided Output instance.
 func (f *field) Render(o protogen.Output) error {
+
+	err := checkTypeRef(f.fieldType.ref)
+	if err == nil {
+		err = f.checkLabel()
+	}
+	if err == nil {
+		err = f.checkMap()
+	}
+	if err != nil {
+		return fmt.Errorf("field %s: %w", f.name, err)
+	}
+
+	opts, err := compactOptions(f.allOptions())
+	if err != nil {
+		return err
+	}
+
 	sb := strings.Builder{}
-	if f.repeated {
-		sb.WriteString("repeated ")
+	if l := f.GetLabel(); l != LabelImplicit {
+		sb.WriteString(l.String())
+		sb.WriteString(" ")
+	}
+	if f.keyType != "" {
+		sb.WriteString(fmt.Sprintf("map<%s, %s>", f.keyType, f.fieldType.name(o)))
+	} else {
+		sb.WriteString(f.fieldType.name(o))
 	}
-	sb.WriteString(f.fieldType)
 	sb.WriteString(" ")
 	sb.WriteString(f.name)
 	sb.WriteString(" = ")
 	sb.WriteString(fmt.Sprintf("%d", f.number))
+	sb.WriteString(opts)
 	sb.WriteString(";")
-	if f.inlineComment != "" {
+	switch {
+	case f.comments.Trailing != "":
+		sb.WriteString(trailingComment(f.comments))
+	case f.inlineComment != "":
 		sb.WriteString(" // ")
 		sb.WriteString(f.inlineComment)
 	}
+
+	err = writeLeadingComments(o, f.comments)
+	if err != nil {
+		return err
+	}
+
 	return o.WriteLines(sb.String())
 }
 
 // NewField creates a new Field with the specified name and parameters.
-// name is the name of the field, and params defines its type, number, and repetition.
+// name is the name of the field, and params defines its type, number, and label.
 func NewField(name string, params FieldParams) Field {
 	return &field{
 		name:          name,
-		fieldType:     params.FieldType,
+		fieldType:     typeUse{ref: params.FieldType},
+		keyType:       params.KeyType,
 		number:        params.Number,
+		label:         params.Label,
 		repeated:      params.Repeated,
+		defaultValue:  params.Default,
 		inlineComment: params.InlineComment,
 	}
 }
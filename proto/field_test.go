@@ -0,0 +1,233 @@
+package proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/activatedio/protogen"
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestField_Render(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	cases := []struct {
+		name    string
+		arrange func() proto.Field
+		assert  func(got []byte, err error)
+	}{
+		{
+			name: "simple",
+			arrange: func() proto.Field {
+				return proto.NewField("name", proto.FieldParams{
+					FieldType: proto.String,
+					Number:    1,
+				})
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal("string name = 1;\n", string(got))
+			},
+		},
+		{
+			name: "type references",
+			arrange: func() proto.Field {
+				return proto.NewField("created", proto.FieldParams{
+					FieldType: proto.NewExternalType("google.protobuf.Timestamp"),
+					Number:    1,
+				})
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal("google.protobuf.Timestamp created = 1;\n", string(got))
+			},
+		},
+		{
+			name: "unknown scalar type",
+			arrange: func() proto.Field {
+				return proto.NewField("count", proto.FieldParams{
+					FieldType: proto.ScalarType("number"),
+					Number:    1,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "field count: unknown scalar type number")
+			},
+		},
+		{
+			name: "invalid external type",
+			arrange: func() proto.Field {
+				return proto.NewField("created", proto.FieldParams{
+					FieldType: proto.NewExternalType("google.protobuf..Timestamp"),
+					Number:    1,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, `field created: invalid type name "google.protobuf..Timestamp"`)
+			},
+		},
+		{
+			name: "missing type",
+			arrange: func() proto.Field {
+				return proto.NewField("created", proto.FieldParams{
+					Number: 1,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "field created: missing type")
+			},
+		},
+		{
+			name: "map",
+			arrange: func() proto.Field {
+				return proto.NewField("labels", proto.FieldParams{
+					FieldType: proto.NewMessage("Label"),
+					KeyType:   proto.String,
+					Number:    2,
+				})
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal("map<string, Label> labels = 2;\n", string(got))
+			},
+		},
+		{
+			name: "options",
+			arrange: func() proto.Field {
+				return proto.NewField("name", proto.FieldParams{
+					FieldType:     proto.String,
+					Number:        3,
+					InlineComment: "display name",
+				}).AddOptions(
+					proto.NewOption("deprecated", proto.NewBoolConstant(true)),
+					proto.NewOption("json_name", proto.NewStringConstant("displayName")),
+					proto.NewOption("(validate.rules).string.min_len", proto.NewIntConstant(1)),
+					proto.NewOption("api.field", proto.NewStringConstant("x")),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`string name = 3 [deprecated = true, json_name = "displayName", (validate.rules).string.min_len = 1, (api.field) = "x"]; // display name
+`, string(got))
+			},
+		},
+		{
+			name: "optional",
+			arrange: func() proto.Field {
+				return proto.NewField("name", proto.FieldParams{
+					FieldType: proto.String,
+					Number:    3,
+					Label:     proto.LabelOptional,
+				})
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal("optional string name = 3;\n", string(got))
+			},
+		},
+		{
+			name: "repeated label",
+			arrange: func() proto.Field {
+				return proto.NewField("names", proto.FieldParams{
+					FieldType: proto.String,
+					Number:    4,
+					Label:     proto.LabelRepeated,
+				})
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal("repeated string names = 4;\n", string(got))
+			},
+		},
+		{
+			name: "optional and repeated",
+			arrange: func() proto.Field {
+				return proto.NewField("names", proto.FieldParams{
+					FieldType: proto.String,
+					Number:    4,
+					Label:     proto.LabelOptional,
+					Repeated:  true,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "field names: labels optional and repeated are mutually exclusive")
+			},
+		},
+		{
+			name: "optional map",
+			arrange: func() proto.Field {
+				return proto.NewField("labels", proto.FieldParams{
+					FieldType: proto.String,
+					KeyType:   proto.String,
+					Number:    2,
+					Label:     proto.LabelOptional,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "field labels: map fields cannot be optional")
+			},
+		},
+		{
+			name: "map with invalid key",
+			arrange: func() proto.Field {
+				return proto.NewField("labels", proto.FieldParams{
+					FieldType: proto.NewMessage("Label"),
+					KeyType:   proto.Double,
+					Number:    2,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "field labels: map key type double must be an integral or string type")
+			},
+		},
+		{
+			name: "repeated map",
+			arrange: func() proto.Field {
+				return proto.NewField("labels", proto.FieldParams{
+					FieldType: proto.NewMessage("Label"),
+					KeyType:   proto.Int64,
+					Number:    2,
+					Repeated:  true,
+				})
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "field labels: map fields cannot be repeated")
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			buf := &bytes.Buffer{}
+			unit := tt.arrange()
+			err := unit.Render(protogen.NewWriterOutput(buf))
+			tt.assert(buf.Bytes(), err)
+		})
+	}
+}
+
+func TestScalarType_IsMapKey(t *testing.T) {
+
+	a := assert.New(t)
+
+	for _, s := range []proto.ScalarType{proto.Int32, proto.Sfixed64, proto.Bool, proto.String} {
+		a.True(s.IsMapKey(), s)
+	}
+	for _, s := range []proto.ScalarType{proto.Double, proto.Float, proto.Bytes, proto.ScalarType("decimal")} {
+		a.False(s.IsMapKey(), s)
+	}
+}
+
+func TestScalarType_Valid(t *testing.T) {
+
+	a := assert.New(t)
+
+	a.True(proto.Sint64.Valid())
+	a.True(proto.Bytes.Valid())
+	a.False(proto.ScalarType("int128").Valid())
+	a.False(proto.ScalarType("").Valid())
+}
@@ -7,37 +7,92 @@ import (
 	"github.com/activatedio/protogen"
 )
 
-// File defines an interface for managing and rendering a protocol buffer file.
+// File defines an interface for managing and rendering a protocol buffer file. Files are created with NewFile or
+// Parse, and the interface cannot be implemented outside this package.
 type File interface {
+	GetPath() string
+	GetPackageName() string
+	SetComments(c Comments) File
+	SetPackageComments(c Comments) File
 	AddImports(i ...Import) File
 	AddOptions(i ...Option) File
+	AddEnums(e ...Enum) File
 	AddMessages(m ...Message) File
+	AddExtends(e ...Extend) File
 	AddServices(s ...Service) File
+	Validate() error
 	Write(w io.Writer) error
+	impl() *file
 }
 
-// file represents a container for a package, imports, messages, and services in a proto file.
+// FileParams defines optional parameters of a proto file, such as its syntax.
+// Edition selects the edition written for SyntaxEditions, which is Edition2023 when empty.
+// Path is the path other files import the file by, which is required when its types are used from other files.
+// Validate makes Write return the error from Validate instead of writing a file which breaks the language spec.
+type FileParams struct {
+	Syntax   Syntax
+	Edition  Edition
+	Path     string
+	Validate bool
+}
+
+// file represents a container for a package, imports, enums, messages, and services in a proto file.
 type file struct {
 	packageName string
+	path        string
+	syntax      Syntax
+	edition     Edition
+	validate    bool
+	project     *project
+	comments    Comments
+	pkgComments Comments
 	imports     []Import
 	options     []Option
+	enums       []Enum
 	messages    []Message
+	extends     []Extend
 	services    []Service
 }
 
-// Write generates and writes the complete contents of the file, including package declaration, imports, messages, and services.
+// Write generates and writes the complete contents of the file, including package declaration, imports, enums, messages, and services.
+// When the file was created with Validate set, nothing is written if Validate returns an error.
 func (f *file) Write(w io.Writer) error {
-	output := protogen.NewWriterOutput(w)
+	ctx, err := f.prepare()
+	if err != nil {
+		return err
+	}
+	return f.render(w, ctx)
+}
+
+// prepare validates the file when it was created with Validate set, and returns the context for writing it.
+func (f *file) prepare() (*fileContext, error) {
+	if f.validate {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return f.applyFile()
+}
+
+// render writes the file using the context returned by prepare.
+func (f *file) render(w io.Writer, ctx *fileContext) error {
+
+	output := &contextOutput{Output: protogen.NewWriterOutput(w), ctx: ctx}
+	imports := ctx.allImports()
 
-	if err := writeProtoHeader(output, f.packageName); err != nil {
+	if err := writeLeadingComments(output, f.comments); err != nil {
 		return err
 	}
 
-	if err := renderElements(output, toRenderers(f.imports)); err != nil {
+	if err := writeProtoHeader(output, f.syntaxStatement(), trailingComment(f.comments), f.packageName, f.pkgComments); err != nil {
 		return err
 	}
 
-	if len(f.imports) > 0 {
+	if err := renderElements(output, toRenderers(imports)); err != nil {
+		return err
+	}
+
+	if len(imports) > 0 {
 		if err := output.WriteLines(""); err != nil {
 			return err
 		}
@@ -53,25 +108,101 @@ func (f *file) Write(w io.Writer) error {
 		}
 	}
 
+	if err := renderElements(output, toRenderers(f.enums)); err != nil {
+		return err
+	}
+
 	if err := renderElements(output, toRenderers(f.messages)); err != nil {
 		return err
 	}
 
+	if err := renderElements(output, toRenderers(f.extends)); err != nil {
+		return err
+	}
+
 	if err := renderElements(output, toRenderers(f.services)); err != nil {
 		return err
 	}
 
+	if len(f.comments.Closing) > 0 {
+		if err := writeClosingComments(output, f.comments); err != nil {
+			return err
+		}
+		return output.WriteLines("")
+	}
+
 	return nil
 }
 
+// syntaxStatement returns the syntax or edition statement of the file.
+func (f *file) syntaxStatement() string {
+	if f.syntax == SyntaxEditions {
+		return fmt.Sprintf("edition = \"%s\";", f.edition)
+	}
+	return fmt.Sprintf("syntax = \"%s\";", f.syntax)
+}
+
 // writeProtoHeader writes the proto syntax and package declaration to the output.
-func writeProtoHeader(output protogen.Output, packageName string) error {
-	return output.WriteLines(
-		"syntax = \"proto3\";",
-		"",
-		fmt.Sprintf("package %s;", packageName),
-		"",
-	)
+// The trailing comment of the file is written after the syntax statement, and the package declaration is written with
+// its own comments. Files without a package have no package declaration.
+func writeProtoHeader(output protogen.Output, syntaxStatement, trailing, packageName string, pc Comments) error {
+	if err := output.WriteLines(syntaxStatement+trailing, ""); err != nil {
+		return err
+	}
+	if packageName == "" {
+		return nil
+	}
+	if err := writeLeadingComments(output, pc); err != nil {
+		return err
+	}
+	return output.WriteLines(fmt.Sprintf("package %s;%s", packageName, trailingComment(pc)), "")
+}
+
+// applyFile returns an error if any element of the file uses constructs which are not valid for its syntax.
+// It also collects the imports of the files declaring the types used by the elements. The returned context is
+// carried by the output the file is rendered into, and lives for a single write.
+func (f *file) applyFile() (*fileContext, error) {
+	err := checkFeatures(f.syntax, targetFile, f.options)
+	if err != nil {
+		return nil, err
+	}
+	ctx := &fileContext{
+		file:   f,
+		syntax: f.syntax,
+	}
+	for _, es := range [][]any{toAny(f.enums), toAny(f.messages), toAny(f.extends), toAny(f.services)} {
+		if err := applyElements(ctx, es); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// Validate checks the whole file against the language spec and returns a *ValidationError holding every violation,
+// such as duplicate names or field numbers, field numbers outside the allowed range and invalid identifiers.
+// Each violation has the path of the element it concerns.
+func (f *file) Validate() error {
+
+	v := &validator{syntax: f.syntax}
+
+	if f.packageName != "" && !packageNamePattern.MatchString(f.packageName) {
+		v.report(fmt.Errorf("invalid package name %q", f.packageName))
+	}
+	v.report(checkFeatures(f.syntax, targetFile, f.options))
+
+	s := scope{}
+	s.declareTypes(v, f.enums, f.messages)
+	for _, sv := range f.services {
+		if ss, ok := sv.(*service); ok {
+			v.report(s.declare("service", ss.name))
+		}
+	}
+
+	for _, es := range [][]any{toAny(f.enums), toAny(f.messages), toAny(f.extends), toAny(f.services)} {
+		validateElements(v, es)
+	}
+
+	return v.err()
 }
 
 // renderElements iterates through a slice of Renderer interfaces and renders each element.
@@ -85,19 +216,60 @@ func renderElements(output protogen.Output, elements []protogen.Renderer) error
 	return nil
 }
 
+// impl returns the file itself, which seals the File interface so that only files of this package can be added to
+// a Project.
+func (f *file) impl() *file {
+	return f
+}
+
+// SetComments sets the comments written above the syntax statement of the file and returns the updated File instance.
+func (f *file) SetComments(c Comments) File {
+	f.comments = c
+	return f
+}
+
+// SetPackageComments sets the comments written with the package statement of the file and returns the updated File
+// instance.
+func (f *file) SetPackageComments(c Comments) File {
+	f.pkgComments = c
+	return f
+}
+
+// GetPath returns the path the file is imported by.
+func (f *file) GetPath() string {
+	return f.path
+}
+
+// GetPackageName returns the package of the file.
+func (f *file) GetPackageName() string {
+	return f.packageName
+}
+
 // AddImports appends one or more Import instances to the file's imports and returns the updated File instance.
+// A path is only imported once. When it is added again with a stronger modifier, the existing import is upgraded
+// in place, with public taking precedence over plain and plain over weak.
 func (f *file) AddImports(i ...Import) File {
-	is := map[string]bool{}
-	for _, _i := range f.imports {
-		is[_i.GetPath()] = true
-	}
-	for _, _i := range i {
-		if !is[_i.GetPath()] {
-			is[_i.GetPath()] = true
-			f.imports = append(f.imports, _i)
+	f.imports = mergeImports(f.imports, i...)
+	return f
+}
+
+// mergeImports returns the imports with the added imports merged in as described by AddImports.
+func mergeImports(imports []Import, added ...Import) []Import {
+	is := map[string]int{}
+	for n, _i := range imports {
+		is[_i.GetPath()] = n
+	}
+	for _, _i := range added {
+		n, ok := is[_i.GetPath()]
+		switch {
+		case !ok:
+			is[_i.GetPath()] = len(imports)
+			imports = append(imports, _i)
+		case _i.GetModifier().strength() > imports[n].GetModifier().strength():
+			imports[n] = _i
 		}
 	}
-	return f
+	return imports
 }
 
 // AddOptions appends one or more Option instances to the file's options list and returns the updated File instance.
@@ -106,21 +278,59 @@ func (f *file) AddOptions(i ...Option) File {
 	return f
 }
 
+// AddEnums appends one or more Enum instances to the file's enum list and returns the updated File.
+// Enums without a package name take the package of the file.
+func (f *file) AddEnums(e ...Enum) File {
+	for _, d := range e {
+		f.declare(d)
+	}
+	f.enums = append(f.enums, e...)
+	return f
+}
+
 // AddMessages appends one or more Message instances to the file's message list and returns the updated File.
+// Messages without a package name take the package of the file.
 func (f *file) AddMessages(m ...Message) File {
+	for _, d := range m {
+		f.declare(d)
+	}
 	f.messages = append(f.messages, m...)
 	return f
 }
 
+// declare records the file as the declaring file of a message or enum, so files using it can import it.
+func (f *file) declare(d any) {
+	if dd, ok := d.(declaration); ok {
+		dd.setFile(f)
+	}
+}
+
+// AddExtends appends one or more Extend blocks to the file and returns the updated File.
+func (f *file) AddExtends(e ...Extend) File {
+	f.extends = append(f.extends, e...)
+	return f
+}
+
 // AddServices appends one or more Service objects to the file and returns the updated File instance.
 func (f *file) AddServices(s ...Service) File {
 	f.services = append(f.services, s...)
 	return f
 }
 
-// NewFile creates a new File instance with the specified package name.
-func NewFile(packageName string) File {
-	return &file{
+// NewFile creates a new File instance with the specified package name. Optional params select the syntax or edition
+// of the file, which is proto3 when omitted.
+func NewFile(packageName string, params ...FileParams) File {
+	f := &file{
 		packageName: packageName,
 	}
+	for _, p := range params {
+		f.syntax = p.Syntax
+		f.edition = p.Edition
+		f.path = p.Path
+		f.validate = p.Validate
+	}
+	if f.syntax == SyntaxEditions && f.edition == "" {
+		f.edition = Edition2023
+	}
+	return f
 }
@@ -57,25 +57,25 @@ package unit;
 					AddMessages(
 						NewMessage("Message1").AddFields(
 							NewField("Field1", FieldParams{
-								FieldType:     "bool",
+								FieldType:     Bool,
 								Number:        1001,
 								Repeated:      false,
 								InlineComment: `@gotags: yaml:"field1"`,
 							}),
 							NewField("Field2", FieldParams{
-								FieldType: "string",
+								FieldType: String,
 								Number:    1002,
 								Repeated:  true,
 							}),
 						),
 						NewMessage("Message2").AddFields(
 							NewField("Field3", FieldParams{
-								FieldType: "number",
+								FieldType: Int64,
 								Number:    1001,
 								Repeated:  false,
 							}),
 							NewField("Field4", FieldParams{
-								FieldType: "string",
+								FieldType: String,
 								Number:    1002,
 								Repeated:  false,
 							}),
@@ -83,8 +83,8 @@ package unit;
 					).AddServices(
 					NewService("Service1").AddMethods(
 						NewMethod("Method1", MethodParams{
-							RequestName:  "Request1",
-							ResponseName: "Response1",
+							RequestType:  NewMessage("Request1"),
+							ResponseType: NewMessage("Response1"),
 						}).AddOptions(
 							NewOption("option1", NewStringConstant("value1")),
 							NewOption("api.option2", NewMessageValueConstant(tfl.NewMessageValue().AddFields(
@@ -93,18 +93,18 @@ package unit;
 							))),
 						),
 						NewMethod("Method2", MethodParams{
-							RequestName:  "Request2",
-							ResponseName: "Response2",
+							RequestType:  NewMessage("Request2"),
+							ResponseType: NewMessage("Response2"),
 						}),
 					),
 					NewService("Service2").AddMethods(
 						NewMethod("Method3", MethodParams{
-							RequestName:  "Request3",
-							ResponseName: "Response3",
+							RequestType:  NewMessage("Request3"),
+							ResponseType: NewMessage("Response3"),
 						}),
 						NewMethod("Method4", MethodParams{
-							RequestName:  "Request4",
-							ResponseName: "Response4",
+							RequestType:  NewMessage("Request4"),
+							ResponseType: NewMessage("Response4"),
 						}),
 					),
 				)
@@ -129,7 +129,7 @@ message Message1 {
 }
 
 message Message2 {
-  number Field3 = 1001;
+  int64 Field3 = 1001;
   string Field4 = 1002;
 }
 
@@ -155,6 +155,744 @@ service Service2 {
 `, string(got))
 			},
 		},
+		{
+			name: "enums",
+			arrange: func() File {
+				return NewFile("unit").AddEnums(
+					NewEnum("Enum1").AddValues(
+						NewEnumValue("ENUM1_UNSPECIFIED", 0),
+						NewEnumValue("ENUM1_VALUE", 1),
+					),
+				).AddMessages(
+					NewMessage("Message1").AddFields(
+						NewField("Field1", FieldParams{
+							FieldType: Bool,
+							Number:    1,
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+package unit;
+
+enum Enum1 {
+  ENUM1_UNSPECIFIED = 0;
+  ENUM1_VALUE = 1;
+}
+
+message Message1 {
+  bool Field1 = 1;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "message and service options",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Message1").AddOptions(
+						NewOption("deprecated", NewBoolConstant(true)),
+					).AddFields(
+						NewField("Field1", FieldParams{
+							FieldType: Int64,
+							Number:    1,
+						}),
+					),
+				).AddServices(
+					NewService("Service1").AddOptions(
+						NewOption("google.api.default_host", NewStringConstant("api.example.com")),
+					).AddMethods(
+						NewMethod("Method1", MethodParams{
+							RequestType:  NewMessage("Request1"),
+							ResponseType: NewMessage("Response1"),
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+package unit;
+
+message Message1 {
+  option deprecated = true;
+  int64 Field1 = 1;
+}
+
+service Service1 {
+  option (google.api.default_host) = "api.example.com";
+  rpc Method1 (Request1) returns (Response1) {
+  }
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "import modifiers",
+			arrange: func() File {
+				return NewFile("unit").AddImports(
+					NewImport("subpath1/path1"),
+					NewImport("subpath2/path2"),
+					// Plain imports are upgraded to public, but not downgraded to weak
+					NewImport("subpath1/path1", ImportPublic),
+					NewImport("subpath2/path2", ImportWeak),
+					NewImport("subpath3/path3", ImportWeak),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+package unit;
+
+import public "subpath1/path1";
+import "subpath2/path2";
+import weak "subpath3/path3";
+
+`, string(got))
+			},
+		},
+		{
+			name: "package and import comments",
+			arrange: func() File {
+				return NewFile("unit").SetPackageComments(Comments{
+					Leading:  "The unit package",
+					Trailing: "pkg",
+				}).AddImports(
+					NewImport("a.proto").SetComments(Comments{Leading: "Money types", Trailing: "used by Order"}),
+					NewImport("b.proto", ImportPublic).SetComments(Comments{Trailing: "forwarded"}),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+// The unit package
+package unit; // pkg
+
+// Money types
+import "a.proto"; // used by Order
+import public "b.proto"; // forwarded
+
+`, string(got))
+			},
+		},
+		{
+			name: "comments",
+			arrange: func() File {
+				return NewFile("unit").SetComments(Comments{
+					Detached: []string{"Copyright example"},
+					Leading:  "Orders API",
+					Trailing: "syntax",
+				}).AddOptions(
+					NewOption("go_package", NewStringConstant("example/unit")).SetComments(Comments{
+						Leading:  "Go package",
+						Trailing: "path",
+					}),
+				).AddEnums(
+					NewEnum("Status").SetComments(Comments{
+						Leading: "Status of an order",
+						Style:   CommentStyleBlock,
+					}).AddValues(
+						NewEnumValue("STATUS_UNSPECIFIED", 0).SetComments(Comments{
+							Trailing: "default",
+						}),
+					),
+				).AddMessages(
+					NewMessage("Order").SetComments(Comments{
+						Leading:  "An order\nplaced by a customer",
+						Trailing: "order",
+						Style:    CommentStyleBlock,
+					}).AddFields(
+						NewField("id", FieldParams{
+							FieldType:     String,
+							Number:        1,
+							InlineComment: "replaced",
+						}).SetComments(Comments{
+							Detached: []string{"Identity"},
+							Leading:  "Unique id",
+							Trailing: "required",
+						}),
+					).AddOneofs(
+						NewOneof("payment").SetComments(Comments{
+							Leading: "Payment method",
+						}),
+					),
+				).AddServices(
+					NewService("Orders").SetComments(Comments{
+						Leading: "Manages orders",
+					}).AddMethods(
+						NewMethod("Get", MethodParams{
+							RequestType:  NewMessage("GetRequest"),
+							ResponseType: NewMessage("Order"),
+						}).SetComments(Comments{
+							Leading:  "Gets an order",
+							Trailing: "read",
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`// Copyright example
+
+// Orders API
+syntax = "proto3"; // syntax
+
+package unit;
+
+// Go package
+option go_package = "example/unit"; // path
+
+/* Status of an order */
+enum Status {
+  STATUS_UNSPECIFIED = 0; // default
+}
+
+/*
+ * An order
+ * placed by a customer
+ */
+message Order { /* order */
+  // Identity
+
+  // Unique id
+  string id = 1; // required
+  // Payment method
+  oneof payment {
+  }
+}
+
+// Manages orders
+service Orders {
+  // Gets an order
+  rpc Get (GetRequest) returns (Order) { // read
+  }
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "proto2",
+			arrange: func() File {
+				return NewFile("unit", FileParams{Syntax: SyntaxProto2}).AddMessages(
+					NewMessage("Search").AddExtensionRanges(
+						NewExtensionRange(100, 199),
+						NewExtensionRangeToMax(1000),
+					).AddFields(
+						NewField("query", FieldParams{
+							FieldType: String,
+							Number:    1,
+							Label:     LabelRequired,
+						}),
+						NewField("page", FieldParams{
+							FieldType: Int32,
+							Number:    2,
+							Label:     LabelOptional,
+							Default:   NewIntConstant(1),
+						}),
+						NewField("kind", FieldParams{
+							FieldType: NewMessage("Kind"),
+							Number:    3,
+							Label:     LabelOptional,
+							Default:   NewIdentifierConstant("KIND_WEB"),
+						}).AddOptions(
+							NewOption("deprecated", NewBoolConstant(true)),
+						),
+						NewField("tags", FieldParams{
+							FieldType: String,
+							KeyType:   String,
+							Number:    4,
+						}),
+						NewGroup("Result", GroupParams{
+							Number: 5,
+							Label:  LabelRepeated,
+						}).AddFields(
+							NewField("url", FieldParams{
+								FieldType: String,
+								Number:    6,
+								Label:     LabelRequired,
+							}),
+						),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto2";
+
+package unit;
+
+message Search {
+  required string query = 1;
+  optional int32 page = 2 [default = 1];
+  optional Kind kind = 3 [default = KIND_WEB, deprecated = true];
+  map<string, string> tags = 4;
+  repeated group Result = 5 {
+    required string url = 6;
+  }
+  extensions 100 to 199;
+  extensions 1000 to max;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "proto2 field without label",
+			arrange: func() File {
+				return NewFile("unit", FileParams{Syntax: SyntaxProto2}).AddMessages(
+					NewMessage("Search").AddFields(
+						NewField("query", FieldParams{
+							FieldType: String,
+							Number:    1,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: field query: proto2 fields require a label")
+			},
+		},
+		{
+			name: "proto3 required field",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Search").AddMessages(
+						NewMessage("Inner").AddFields(
+							NewField("query", FieldParams{
+								FieldType: String,
+								Number:    1,
+								Label:     LabelRequired,
+							}),
+						),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: message Inner: field query: required fields are not allowed in proto3")
+			},
+		},
+		{
+			name: "proto3 default value",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Search").AddFields(
+						NewField("page", FieldParams{
+							FieldType: Int32,
+							Number:    1,
+							Default:   NewIntConstant(1),
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: field page: default values are not allowed in proto3")
+			},
+		},
+		{
+			name: "proto3 group",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Search").AddFields(
+						NewGroup("Result", GroupParams{Number: 1}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: group Result: groups are not allowed in proto3")
+			},
+		},
+		{
+			name: "proto3 extension range",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Search").AddExtensionRanges(NewExtensionRange(100, 199)),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: extension ranges are not allowed in proto3")
+			},
+		},
+		{
+			name: "proto3 enum without zero value",
+			arrange: func() File {
+				return NewFile("unit").AddEnums(
+					NewEnum("Kind").AddValues(NewEnumValue("KIND_WEB", 1)),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "enum Kind: the first value must be zero in proto3")
+			},
+		},
+		{
+			name: "editions",
+			arrange: func() File {
+				return NewFile("unit", FileParams{Syntax: SyntaxEditions}).AddOptions(
+					NewFieldPresenceFeature(FieldPresenceImplicit),
+					NewUTF8ValidationFeature(UTF8ValidationNone),
+				).AddEnums(
+					NewEnum("Kind").AddOptions(
+						NewEnumTypeFeature(EnumTypeClosed),
+						NewJSONFormatFeature(JSONFormatLegacyBestEffort),
+					).AddValues(
+						NewEnumValue("KIND_WEB", 1),
+					),
+				).AddMessages(
+					NewMessage("Search").AddOptions(
+						NewJSONFormatFeature(JSONFormatAllow),
+					).AddReserved(
+						NewReservedNames("legacy"),
+					).AddFields(
+						NewField("query", FieldParams{
+							FieldType: String,
+							Number:    1,
+							Default:   NewStringConstant("all"),
+						}).AddOptions(
+							NewFieldPresenceFeature(FieldPresenceExplicit),
+						),
+						NewField("pages", FieldParams{
+							FieldType: Int32,
+							Number:    2,
+							Label:     LabelRepeated,
+						}).AddOptions(
+							NewRepeatedFieldEncodingFeature(RepeatedFieldEncodingExpanded),
+						),
+						NewField("result", FieldParams{
+							FieldType: NewMessage("Result"),
+							Number:    3,
+						}).AddOptions(
+							NewMessageEncodingFeature(MessageEncodingDelimited),
+						),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`edition = "2023";
+
+package unit;
+
+option features.field_presence = IMPLICIT;
+option features.utf8_validation = NONE;
+
+enum Kind {
+  option features.enum_type = CLOSED;
+  option features.json_format = LEGACY_BEST_EFFORT;
+  KIND_WEB = 1;
+}
+
+message Search {
+  option features.json_format = ALLOW;
+  reserved legacy;
+  string query = 1 [default = "all", features.field_presence = EXPLICIT];
+  repeated int32 pages = 2 [features.repeated_field_encoding = EXPANDED];
+  Result result = 3 [features.message_encoding = DELIMITED];
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "editions optional label",
+			arrange: func() File {
+				return NewFile("unit", FileParams{Syntax: SyntaxEditions, Edition: Edition2023}).AddMessages(
+					NewMessage("Search").AddFields(
+						NewField("query", FieldParams{
+							FieldType: String,
+							Number:    1,
+							Label:     LabelOptional,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: field query: optional fields are not allowed in editions, use features.field_presence")
+			},
+		},
+		{
+			name: "feature on wrong target",
+			arrange: func() File {
+				return NewFile("unit", FileParams{Syntax: SyntaxEditions}).AddMessages(
+					NewMessage("Search").AddOptions(
+						NewFieldPresenceFeature(FieldPresenceExplicit),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Search: option features.field_presence: cannot be set on a message")
+			},
+		},
+		{
+			name: "feature outside editions",
+			arrange: func() File {
+				return NewFile("unit").AddOptions(
+					NewUTF8ValidationFeature(UTF8ValidationVerify),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "option features.utf8_validation: features are only allowed in editions")
+			},
+		},
+		{
+			name: "extends",
+			arrange: func() File {
+				return NewFile("api").AddImports(
+					NewImport("google/protobuf/descriptor.proto"),
+				).AddMessages(
+					NewMessage("Rule").AddFields(
+						NewField("path", FieldParams{
+							FieldType: String,
+							Number:    1,
+						}),
+					).AddExtends(
+						NewExtend(NewExternalType("google.protobuf.FieldOptions")).AddFields(
+							NewField("rule", FieldParams{
+								FieldType: NewMessage("Rule"),
+								Number:    50002,
+							}),
+						),
+					),
+				).AddExtends(
+					NewExtend(NewExternalType("google.protobuf.MethodOptions")).SetComments(Comments{
+						Leading: "Method options",
+					}).AddFields(
+						NewField("http_path", FieldParams{
+							FieldType: String,
+							Number:    50001,
+						}),
+					),
+				).AddServices(
+					NewService("Search").AddMethods(
+						NewMethod("Find", MethodParams{
+							RequestType:  NewMessage("FindRequest"),
+							ResponseType: NewMessage("FindResponse"),
+						}).AddOptions(
+							NewOption("api.http_path", NewStringConstant("/find")),
+						),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+package api;
+
+import "google/protobuf/descriptor.proto";
+
+message Rule {
+  string path = 1;
+  extend .google.protobuf.FieldOptions {
+    Rule rule = 50002;
+  }
+}
+
+// Method options
+extend .google.protobuf.MethodOptions {
+  string http_path = 50001;
+}
+
+service Search {
+  rpc Find (FindRequest) returns (FindResponse) {
+    option (api.http_path) = "/find";
+  }
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "proto3 extend of a message",
+			arrange: func() File {
+				return NewFile("api").AddExtends(
+					NewExtend(NewExternalType("Rule")).AddFields(
+						NewField("path", FieldParams{
+							FieldType: String,
+							Number:    100,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "extend Rule: only custom options may be defined in proto3")
+			},
+		},
+		{
+			name: "proto2 extend",
+			arrange: func() File {
+				return NewFile("api", FileParams{Syntax: SyntaxProto2}).AddMessages(
+					NewMessage("Rule").AddExtensionRanges(NewExtensionRange(100, 199)),
+				).AddExtends(
+					NewExtend(NewExternalType("Rule")).AddFields(
+						NewField("path", FieldParams{
+							FieldType: String,
+							Number:    100,
+							Label:     LabelOptional,
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto2";
+
+package api;
+
+message Rule {
+  extensions 100 to 199;
+}
+
+extend Rule {
+  optional string path = 100;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "cross package references",
+			arrange: func() File {
+
+				money := NewEnum("Currency")
+				amount := NewMessage("Amount")
+				NewFile("common.money", FileParams{Path: "common/money.proto"}).
+					AddEnums(money).
+					AddMessages(amount)
+
+				address := NewMessage("Address")
+				NewFile("orders", FileParams{Path: "orders/address.proto"}).AddMessages(address)
+
+				line := NewMessage("Line")
+				order := NewMessage("Order").AddMessages(line).AddFields(
+					NewField("total", FieldParams{
+						FieldType: amount,
+						Number:    1,
+					}),
+					NewField("currency", FieldParams{
+						FieldType: money,
+						Number:    2,
+					}),
+					NewField("address", FieldParams{
+						FieldType: address,
+						Number:    3,
+					}),
+					NewField("lines", FieldParams{
+						FieldType: line,
+						Number:    4,
+						Repeated:  true,
+					}),
+					NewField("created", FieldParams{
+						FieldType: NewExternalType("google.protobuf.Timestamp", "google/protobuf/timestamp.proto"),
+						Number:    5,
+					}),
+				)
+
+				return NewFile("orders", FileParams{Path: "orders/order.proto"}).AddMessages(order).AddServices(
+					NewService("Orders").AddMethods(
+						NewMethod("Total", MethodParams{
+							RequestType:  order,
+							ResponseType: amount,
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+package orders;
+
+import "common/money.proto";
+import "orders/address.proto";
+import "google/protobuf/timestamp.proto";
+
+message Order {
+  .common.money.Amount total = 1;
+  .common.money.Currency currency = 2;
+  Address address = 3;
+  repeated Order.Line lines = 4;
+  .google.protobuf.Timestamp created = 5;
+  message Line {
+  }
+}
+
+service Orders {
+  rpc Total (Order) returns (.common.money.Amount) {
+  }
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "reference to a package named like the end of the file package",
+			arrange: func() File {
+				address := NewMessage("Address")
+				NewFile("orders", FileParams{Path: "orders/address.proto"}).AddMessages(address)
+				return NewFile("acme.orders").AddMessages(
+					NewMessage("Customer").AddFields(
+						NewField("address", FieldParams{
+							FieldType: address,
+							Number:    1,
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`syntax = "proto3";
+
+package acme.orders;
+
+import "orders/address.proto";
+
+message Customer {
+  .orders.Address address = 1;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "reference to file without path",
+			arrange: func() File {
+				amount := NewMessage("Amount")
+				NewFile("common.money").AddMessages(amount)
+				return NewFile("orders").AddMessages(
+					NewMessage("Order").AddFields(
+						NewField("total", FieldParams{
+							FieldType: amount,
+							Number:    1,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Order: field total: Amount is declared in a file without a path")
+			},
+		},
+		{
+			name: "validate before write",
+			arrange: func() File {
+				return NewFile("unit", FileParams{Validate: true}).AddMessages(
+					NewMessage("Order").AddFields(
+						NewField("id", FieldParams{FieldType: String, Number: 19000}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.EqualError(err, "message Order > field id: number 19000 is reserved for the protobuf implementation")
+				a.Empty(got)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -167,3 +905,193 @@ service Service2 {
 	}
 
 }
+
+func TestFile_Write_Repeated(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	amount := NewMessage("Amount")
+	NewFile("common.money", FileParams{Path: "common/money.proto"}).AddMessages(amount)
+	order := NewMessage("Order").AddFields(
+		NewField("total", FieldParams{
+			FieldType: amount,
+			Number:    1,
+		}),
+	)
+	f := NewFile("orders").AddMessages(order)
+
+	first := &bytes.Buffer{}
+	r.NoError(f.Write(first))
+	second := &bytes.Buffer{}
+	r.NoError(f.Write(second))
+
+	a.Equal(first.String(), second.String())
+	a.Empty(f.(*file).imports)
+	a.Equal("orders", order.GetPackageName())
+	a.Equal("common.money", amount.GetPackageName())
+}
+
+func TestFile_Validate(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	tests := []struct {
+		name    string
+		arrange func() File
+		assert  func(err error)
+	}{
+		{
+			name: "valid",
+			arrange: func() File {
+				return NewFile("unit.v1").AddMessages(
+					NewMessage("Order").AddFields(
+						NewField("id", FieldParams{FieldType: String, Number: 1}),
+						NewField("max", FieldParams{FieldType: Int64, Number: maxFieldNumber}),
+					),
+				).AddServices(
+					NewService("Orders").AddMethods(
+						NewMethod("Get", MethodParams{RequestType: NewMessage("Request"), ResponseType: NewMessage("Response")}),
+					),
+				)
+			},
+			assert: func(err error) {
+				r.NoError(err)
+			},
+		},
+		{
+			name: "every violation",
+			arrange: func() File {
+				status := NewEnum("Status").AddValues(NewEnumValue("ACTIVE", 0))
+				return NewFile("unit").AddEnums(status).AddMessages(
+					NewMessage("Order").AddFields(
+						NewField("id", FieldParams{FieldType: String, Number: 1}),
+						NewField("code", FieldParams{FieldType: String, Number: 1}),
+						NewField("total", FieldParams{FieldType: Int64, Number: 19500}),
+						NewField("big", FieldParams{FieldType: Int64, Number: maxFieldNumber + 1}),
+						NewField("bad-name", FieldParams{FieldType: String, Number: 5}),
+					).AddOneofs(
+						NewOneof("payload"),
+					).AddEnums(
+						NewEnum("Kind"),
+					),
+					NewMessage("Order"),
+					NewMessage("ACTIVE"),
+				).AddServices(
+					NewService("").AddMethods(
+						NewMethod("Get", MethodParams{RequestType: String, ResponseType: NewMessage("Response")}),
+					),
+				)
+			},
+			assert: func(err error) {
+				var verr *ValidationError
+				r.ErrorAs(err, &verr)
+				a.Equal([]string{
+					"message Order conflicts with message Order",
+					"message ACTIVE conflicts with enum value ACTIVE",
+					"message Order: field code reuses number 1 of field id",
+					"message Order > field total: number 19500 is reserved for the protobuf implementation",
+					"message Order > field big: number 536870912 is outside the range 1 to 536870911",
+					"message Order > field bad-name: invalid identifier \"bad-name\"",
+					"message Order > oneof payload: oneofs must have at least one field",
+					"message Order > enum Kind: enums must have at least one value",
+					"service: name is empty",
+					"service > method Get: request: string is not a message type",
+				}, violations(verr))
+			},
+		},
+		{
+			name: "backwards reserved ranges",
+			arrange: func() File {
+				return NewFile("unit").AddEnums(
+					NewEnum("Status").AddValues(NewEnumValue("STATUS_UNSPECIFIED", 0)).AddReserved(NewReservedRange(9, 3)),
+				).AddMessages(
+					NewMessage("Order").AddReserved(NewReservedRange(10, 5), NewReservedRange(2, 2)),
+				)
+			},
+			assert: func(err error) {
+				var verr *ValidationError
+				r.ErrorAs(err, &verr)
+				a.Equal([]string{
+					"enum Status: reserved range 9 to 3 ends before it starts",
+					"message Order: reserved range 10 to 5 ends before it starts",
+				}, violations(verr))
+			},
+		},
+		{
+			name: "members named like nested declarations",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("A").AddFields(
+						NewField("B", FieldParams{FieldType: String, Number: 1}),
+						NewField("KIND_UNSPECIFIED", FieldParams{FieldType: String, Number: 2}),
+						NewField("id", FieldParams{FieldType: String, Number: 3}),
+						NewField("id", FieldParams{FieldType: String, Number: 4}),
+					).AddOneofs(
+						NewOneof("Kind").AddFields(NewField("text", FieldParams{FieldType: String, Number: 5})),
+						NewOneof("id").AddFields(NewField("data", FieldParams{FieldType: Bytes, Number: 6})),
+					).AddMessages(
+						NewMessage("B"),
+					).AddEnums(
+						NewEnum("Kind").AddValues(NewEnumValue("KIND_UNSPECIFIED", 0)),
+					),
+				)
+			},
+			assert: func(err error) {
+				var verr *ValidationError
+				r.ErrorAs(err, &verr)
+				a.Equal([]string{
+					"message A: field name id is used more than once",
+					"message A: field B conflicts with message B",
+					"message A: field KIND_UNSPECIFIED conflicts with enum value KIND_UNSPECIFIED",
+					"message A: oneof Kind conflicts with enum Kind",
+					"message A: oneof id conflicts with field id",
+				}, violations(verr))
+			},
+		},
+		{
+			name: "map in oneof",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Order").AddOneofs(
+						NewOneof("payload").AddFields(
+							NewField("tags", FieldParams{KeyType: String, FieldType: String, Number: 1}),
+						),
+					),
+				)
+			},
+			assert: func(err error) {
+				r.EqualError(err, "message Order > oneof payload: field tags cannot be a map")
+			},
+		},
+		{
+			name: "syntax",
+			arrange: func() File {
+				return NewFile("unit").AddMessages(
+					NewMessage("Order").AddFields(
+						NewField("id", FieldParams{FieldType: String, Number: 1, Label: LabelRequired}),
+					),
+				)
+			},
+			assert: func(err error) {
+				r.EqualError(err, "message Order > field id: required fields are not allowed in proto3")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(_ *testing.T) {
+			tt.assert(tt.arrange().Validate())
+		})
+	}
+
+}
+
+func violations(err *ValidationError) []string {
+	var got []string
+	for _, v := range err.Violations {
+		got = append(got, v.Error())
+	}
+	return got
+}
@@ -0,0 +1,163 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/activatedio/protogen"
+)
+
+// GroupParams defines the number and label of a group field.
+type GroupParams struct {
+	Number int32
+	Label  FieldLabel
+}
+
+// Group represents a proto2 group, a field which declares its message type inline.
+type Group interface {
+	Field
+	AddFields(...Field) Group
+}
+
+// group represents a named group field with the fields of its inline message type.
+type group struct {
+	name     string
+	number   int32
+	label    FieldLabel
+	comments Comments
+	options  []Option
+	fields   []Field
+}
+
+// GetName returns the name of the group.
+func (g *group) GetName() string {
+	return g.name
+}
+
+// GetNumber returns the number of the group field.
+func (g *group) GetNumber() int32 {
+	return g.number
+}
+
+// GetLabel returns the label of the group field.
+func (g *group) GetLabel() FieldLabel {
+	return g.label
+}
+
+// SetComments sets the comments written with the group and returns the updated Field.
+func (g *group) SetComments(c Comments) Field {
+	g.comments = c
+	return g
+}
+
+// AddOptions appends one or more Option instances to the group field and returns the updated Field.
+func (g *group) AddOptions(o ...Option) Field {
+	g.options = append(g.options, o...)
+	return g
+}
+
+// AddFields appends one or more Field elements to the group and returns the updated Group.
+func (g *group) AddFields(f ...Field) Group {
+	g.fields = append(g.fields, f...)
+	return g
+}
+
+// checkName returns an error if the name of the group does not start with a capital letter.
+func (g *group) checkName() error {
+	if g.name == "" || !unicode.IsUpper([]rune(g.name)[0]) {
+		return errors.New("group names must start with a capital letter")
+	}
+	return nil
+}
+
+// syntaxError returns an error if the group is used outside proto2, or if its options or the labels of its fields
+// are not valid for the syntax.
+func (g *group) syntaxError(s Syntax) error {
+	if s != SyntaxProto2 {
+		return fmt.Errorf("groups are not allowed in %s", s)
+	}
+	if err := checkFeatures(s, targetField, g.options); err != nil {
+		return err
+	}
+	return checkLabelsRequired(s, g.fields)
+}
+
+// applyFile returns an error if the group or its fields are not valid for the syntax.
+func (g *group) applyFile(ctx *fileContext) error {
+
+	err := g.syntaxError(ctx.syntax)
+	if err == nil {
+		err = applyElements(ctx, g.fields)
+	}
+	if err != nil {
+		return fmt.Errorf("group %s: %w", g.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the group and its fields.
+func (g *group) validate(v *validator) {
+	v.within("group", g.name, func() {
+		v.report(g.checkName())
+		v.report(checkFieldNumber(g.number))
+		v.report(g.syntaxError(v.syntax))
+		v.reportAll(fieldErrors(g.fields, nil, nil))
+		validateElements(v, g.fields)
+	})
+}
+
+// Render writes the group field and its inline fields to the provided Output.
+func (g *group) Render(o protogen.Output) error {
+
+	err := g.checkName()
+	if err != nil {
+		return fmt.Errorf("group %s: %w", g.name, err)
+	}
+
+	opts, err := compactOptions(g.options)
+	if err != nil {
+		return err
+	}
+
+	sb := strings.Builder{}
+	if g.label != LabelImplicit {
+		sb.WriteString(g.label.String())
+		sb.WriteString(" ")
+	}
+	sb.WriteString(fmt.Sprintf("group %s = %d%s {%s", g.name, g.number, opts, trailingComment(g.comments)))
+
+	err = writeLeadingComments(o, g.comments)
+	if err != nil {
+		return err
+	}
+
+	err = o.WriteLines(sb.String())
+	if err != nil {
+		return err
+	}
+
+	io := newBlockOutput(o)
+
+	err = renderElements(io, toRenderers(g.fields))
+	if err != nil {
+		return err
+	}
+
+	err = writeClosingComments(io, g.comments)
+	if err != nil {
+		return err
+	}
+
+	return o.WriteLines("}")
+}
+
+// NewGroup creates a new Group with the specified name, which must start with a capital letter, and parameters.
+func NewGroup(name string, params GroupParams) Group {
+	return &group{
+		name:   name,
+		number: params.Number,
+		label:  params.Label,
+	}
+}
@@ -6,27 +6,91 @@ import (
 	"github.com/activatedio/protogen"
 )
 
+// ImportModifier selects the kind of an import statement.
+type ImportModifier int
+
+// ImportDefault is a plain import.
+// ImportPublic is an import which is also made available to files importing this file.
+// ImportWeak is an import of a file which may be absent.
+const (
+	ImportDefault ImportModifier = iota
+	ImportPublic
+	ImportWeak
+)
+
+// String returns the keyword of the modifier, which is empty for ImportDefault.
+func (m ImportModifier) String() string {
+	switch m {
+	case ImportPublic:
+		return "public"
+	case ImportWeak:
+		return "weak"
+	default:
+		return ""
+	}
+}
+
+// strength orders modifiers when the same path is imported more than once: public over plain over weak.
+func (m ImportModifier) strength() int {
+	switch m {
+	case ImportPublic:
+		return 2
+	case ImportWeak:
+		return 0
+	default:
+		return 1
+	}
+}
+
 // Import represents an import in the file
 type Import interface {
 	protogen.Renderer
 	GetPath() string
+	GetModifier() ImportModifier
+	SetComments(Comments) Import
 }
 
 type importStatement struct {
-	path string
+	path     string
+	modifier ImportModifier
+	comments Comments
 }
 
 func (i *importStatement) GetPath() string {
 	return i.path
 }
 
+// GetModifier returns the modifier of the import.
+func (i *importStatement) GetModifier() ImportModifier {
+	return i.modifier
+}
+
+// SetComments sets the comments written with the import and returns the updated Import.
+func (i *importStatement) SetComments(c Comments) Import {
+	i.comments = c
+	return i
+}
+
 func (i *importStatement) Render(o protogen.Output) error {
-	return o.WriteLines(fmt.Sprintf("import \"%s\";", i.path))
+
+	err := writeLeadingComments(o, i.comments)
+	if err != nil {
+		return err
+	}
+
+	if i.modifier == ImportDefault {
+		return o.WriteLines(fmt.Sprintf("import \"%s\";%s", i.path, trailingComment(i.comments)))
+	}
+	return o.WriteLines(fmt.Sprintf("import %s \"%s\";%s", i.modifier, i.path, trailingComment(i.comments)))
 }
 
-// NewImport creates a new Import instance with the specified path.
-func NewImport(path string) Import {
-	return &importStatement{
+// NewImport creates a new Import instance with the specified path and an optional public or weak modifier.
+func NewImport(path string, modifier ...ImportModifier) Import {
+	i := &importStatement{
 		path: path,
 	}
+	for _, m := range modifier {
+		i.modifier = m
+	}
+	return i
 }
@@ -0,0 +1,302 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind identifies the kind of a token read from proto source.
+type tokenKind int
+
+// tokenEOF marks the end of the source.
+// tokenIdent is an identifier or keyword.
+// tokenInt is an integer literal in decimal, octal or hexadecimal form.
+// tokenFloat is a floating point literal.
+// tokenString is a string literal, holding its content without quotes.
+// tokenSymbol is a single punctuation character.
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenInt
+	tokenFloat
+	tokenString
+	tokenSymbol
+)
+
+// String returns a description of the token kind used in error messages.
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of file"
+	case tokenIdent:
+		return "identifier"
+	case tokenInt:
+		return "integer"
+	case tokenFloat:
+		return "float"
+	case tokenString:
+		return "string"
+	default:
+		return "symbol"
+	}
+}
+
+// comment is a comment read from proto source, which is attached to the token following it.
+// A trailing comment starts on the line the previous token ends on.
+type comment struct {
+	text     string
+	block    bool
+	line     int
+	endLine  int
+	trailing bool
+}
+
+// token is a single token with its position and the comments preceding it.
+type token struct {
+	kind     tokenKind
+	text     string
+	line     int
+	column   int
+	comments []comment
+}
+
+// String describes the token for error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return t.kind.String()
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lexer splits proto source into tokens.
+type lexer struct {
+	src      []rune
+	pos      int
+	line     int
+	column   int
+	prevLine int
+}
+
+// newLexer creates a lexer for the source.
+func newLexer(src string) *lexer {
+	return &lexer{
+		src:    []rune(src),
+		line:   1,
+		column: 1,
+	}
+}
+
+// peekRune returns the rune n positions ahead, or 0 at the end of the source.
+func (l *lexer) peekRune(n int) rune {
+	if l.pos+n >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+n]
+}
+
+// advance consumes a single rune, tracking the line and column.
+func (l *lexer) advance() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+// errorf returns a ParseError at the line and column.
+func (l *lexer) errorf(line, column int, format string, args ...any) error {
+	return &ParseError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// tokens reads all tokens of the source, ending with a tokenEOF holding the comments at the end of the source.
+func (l *lexer) tokens() ([]token, error) {
+	var ts []token
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+		if t.kind == tokenEOF {
+			return ts, nil
+		}
+	}
+}
+
+// next reads the next token and the comments preceding it.
+func (l *lexer) next() (token, error) {
+
+	var comments []comment
+
+	for {
+		for l.pos < len(l.src) && unicode.IsSpace(l.peekRune(0)) {
+			l.advance()
+		}
+		if l.peekRune(0) != '/' || (l.peekRune(1) != '/' && l.peekRune(1) != '*') {
+			break
+		}
+		c, err := l.comment()
+		if err != nil {
+			return token{}, err
+		}
+		comments = append(comments, c)
+	}
+
+	t := token{line: l.line, column: l.column, comments: comments}
+
+	r := l.peekRune(0)
+	switch {
+	case l.pos >= len(l.src):
+		t.kind = tokenEOF
+	case r == '_' || unicode.IsLetter(r):
+		t.kind = tokenIdent
+		t.text = l.scan(func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) })
+	case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(l.peekRune(1))):
+		t.kind, t.text = l.number()
+	case r == '"' || r == '\'':
+		text, err := l.string()
+		if err != nil {
+			return token{}, err
+		}
+		t.kind, t.text = tokenString, text
+	default:
+		t.kind, t.text = tokenSymbol, string(l.advance())
+	}
+
+	l.prevLine = l.line
+
+	return t, nil
+}
+
+// scan consumes runes while they match and returns them.
+func (l *lexer) scan(match func(rune) bool) string {
+	start := l.pos
+	for l.pos < len(l.src) && match(l.peekRune(0)) {
+		l.advance()
+	}
+	return string(l.src[start:l.pos])
+}
+
+// number consumes an integer or float literal.
+func (l *lexer) number() (tokenKind, string) {
+
+	hex := l.peekRune(0) == '0' && (l.peekRune(1) == 'x' || l.peekRune(1) == 'X')
+	start := l.pos
+
+	for l.pos < len(l.src) {
+		r := l.peekRune(0)
+		switch {
+		case r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		case (r == '+' || r == '-') && !hex && l.pos > start && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E'):
+		default:
+			return numberKind(string(l.src[start:l.pos]), hex), string(l.src[start:l.pos])
+		}
+		l.advance()
+	}
+
+	return numberKind(string(l.src[start:]), hex), string(l.src[start:])
+}
+
+// numberKind returns whether the text of a number literal is an integer or a float.
+func numberKind(text string, hex bool) tokenKind {
+	if !hex && strings.ContainsAny(text, ".eE") {
+		return tokenFloat
+	}
+	return tokenInt
+}
+
+// string consumes a string literal and returns its content as written, without the quotes. Double quotes within
+// single-quoted strings are escaped so the content can be written between double quotes.
+func (l *lexer) string() (string, error) {
+
+	line, column := l.line, l.column
+	quote := l.advance()
+	sb := strings.Builder{}
+
+	for {
+		if l.pos >= len(l.src) || l.peekRune(0) == '\n' {
+			return "", l.errorf(line, column, "unterminated string")
+		}
+		r := l.advance()
+		switch {
+		case r == quote:
+			return sb.String(), nil
+		case r == '\\':
+			if l.pos >= len(l.src) {
+				return "", l.errorf(line, column, "unterminated string")
+			}
+			sb.WriteRune(r)
+			sb.WriteRune(l.advance())
+		case r == '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// comment consumes a line or block comment and returns its text without the comment markers.
+func (l *lexer) comment() (comment, error) {
+
+	c := comment{line: l.line, trailing: l.prevLine == l.line}
+	column := l.column
+
+	l.advance()
+	if l.advance() == '/' {
+		text := l.scan(func(r rune) bool { return r != '\n' })
+		c.text = strings.TrimRight(strings.TrimPrefix(text, " "), " \t\r")
+		c.endLine = l.line
+		return c, nil
+	}
+
+	c.block = true
+	start := l.pos
+	for {
+		if l.pos >= len(l.src) {
+			return comment{}, l.errorf(c.line, column, "unterminated comment")
+		}
+		if l.peekRune(0) == '*' && l.peekRune(1) == '/' {
+			break
+		}
+		l.advance()
+	}
+	c.text = blockCommentText(string(l.src[start:l.pos]))
+	c.endLine = l.line
+	l.advance()
+	l.advance()
+
+	return c, nil
+}
+
+// blockCommentText returns the text of a block comment, removing the leading asterisk of each line and the empty
+// lines following /* and preceding */.
+func blockCommentText(raw string) string {
+
+	lines := strings.Split(raw, "\n")
+	if len(lines) == 1 {
+		return strings.TrimSpace(raw)
+	}
+
+	for n, line := range lines {
+		line = strings.TrimLeft(line, " \t")
+		line = strings.TrimPrefix(line, "*")
+		lines[n] = strings.TrimRight(strings.TrimPrefix(line, " "), " \t")
+	}
+	if lines[0] == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
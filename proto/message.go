@@ -11,16 +11,52 @@ import (
 type Message interface {
 	protogen.Renderer
 	GetName() string
+	// GetTypeName returns the dotted name of the message within its package, such as Order.LineItem for nested messages
+	GetTypeName() string
 	SetPackageName(string) Message
 	GetPackageName() string
+	SetComments(Comments) Message
+	AddOptions(...Option) Message
 	AddFields(...Field) Message
+	AddOneofs(...Oneof) Message
+	AddReserved(...Reserved) Message
+	AddExtensionRanges(...ExtensionRange) Message
+	AddMessages(...Message) Message
+	AddEnums(...Enum) Message
+	AddExtends(...Extend) Message
 }
 
 // message represents a struct that defines a named message with a collection of structured fields.
 type message struct {
 	name        string
 	packageName string
+	parent      Message
+	file        *file
+	comments    Comments
+	options     []Option
 	fields      []Field
+	oneofs      []Oneof
+	reserved    []Reserved
+	extensions  []ExtensionRange
+	messages    []Message
+	enums       []Enum
+	extends     []Extend
+}
+
+func (m *message) setParent(p Message) {
+	m.parent = p
+}
+
+func (m *message) setFile(f *file) {
+	m.file = f
+}
+
+// declaringFile returns the file the message is declared in. Nested messages are declared in the file of their parent.
+func (m *message) declaringFile() *file {
+	if d, ok := m.parent.(declaration); ok {
+		return d.declaringFile()
+	}
+	return m.file
 }
 
 func (m *message) SetPackageName(s string) Message {
@@ -28,43 +64,189 @@ func (m *message) SetPackageName(s string) Message {
 	return m
 }
 
+// GetPackageName returns the package of the message. Nested messages share the package of their parent, and messages without
+// a package name take the package of the file declaring them.
 func (m *message) GetPackageName() string {
+	if m.parent != nil {
+		return m.parent.GetPackageName()
+	}
+	if m.packageName == "" && m.file != nil {
+		return m.file.packageName
+	}
 	return m.packageName
 }
 
+// SetComments sets the comments written with the message and returns the updated Message instance.
+func (m *message) SetComments(c Comments) Message {
+	m.comments = c
+	return m
+}
+
+// AddOptions appends one or more Option instances to the message and returns the updated Message instance.
+// Options are rendered at the top of the message body.
+func (m *message) AddOptions(o ...Option) Message {
+	m.options = append(m.options, o...)
+	return m
+}
+
 // AddFields adds one or more Field elements to the message and returns the updated Message instance.
 func (m *message) AddFields(f ...Field) Message {
 	m.fields = append(m.fields, f...)
 	return m
 }
 
+// AddOneofs adds one or more Oneof groups to the message and returns the updated Message instance.
+func (m *message) AddOneofs(o ...Oneof) Message {
+	m.oneofs = append(m.oneofs, o...)
+	return m
+}
+
+// AddReserved adds one or more Reserved statements to the message and returns the updated Message instance.
+func (m *message) AddReserved(r ...Reserved) Message {
+	m.reserved = append(m.reserved, r...)
+	return m
+}
+
+// AddExtensionRanges adds one or more proto2 extension ranges to the message and returns the updated Message instance.
+func (m *message) AddExtensionRanges(e ...ExtensionRange) Message {
+	m.extensions = append(m.extensions, e...)
+	return m
+}
+
+// AddMessages nests one or more Message declarations inside the message and returns the updated Message instance.
+func (m *message) AddMessages(nm ...Message) Message {
+	for _, n := range nm {
+		if ns, ok := n.(nestable); ok {
+			ns.setParent(m)
+		}
+	}
+	m.messages = append(m.messages, nm...)
+	return m
+}
+
+// AddEnums nests one or more Enum declarations inside the message and returns the updated Message instance.
+func (m *message) AddEnums(e ...Enum) Message {
+	for _, n := range e {
+		if ns, ok := n.(nestable); ok {
+			ns.setParent(m)
+		}
+	}
+	m.enums = append(m.enums, e...)
+	return m
+}
+
+// AddExtends nests one or more Extend blocks inside the message and returns the updated Message instance.
+func (m *message) AddExtends(e ...Extend) Message {
+	m.extends = append(m.extends, e...)
+	return m
+}
+
 // GetName returns the name of the message.
 func (m *message) GetName() string {
 	return m.name
 }
 
+// GetTypeName returns the dotted name of the message within its package.
+func (m *message) GetTypeName() string {
+	return typeName(m.parent, m.name)
+}
+
+// allFields returns the fields of the message followed by the fields of its oneofs.
+func (m *message) allFields() []Field {
+	fs := append([]Field{}, m.fields...)
+	for _, o := range m.oneofs {
+		fs = append(fs, o.GetFields()...)
+	}
+	return fs
+}
+
+// checkFields returns an error for the first field of the message, including those within oneofs, which shares
+// a name or number with another field, uses a reserved name or number, or uses a number within an extension range.
+func (m *message) checkFields() error {
+	if errs := fieldErrors(m.allFields(), m.reserved, m.extensions); len(errs) > 0 {
+		return fmt.Errorf("message %s: %w", m.name, errs[0])
+	}
+	return nil
+}
+
+// syntaxError returns an error if the message uses constructs which are not valid for the syntax: extension ranges
+// are not allowed in proto3, and proto2 fields need a label.
+func (m *message) syntaxError(s Syntax) error {
+	if s == SyntaxProto3 && len(m.extensions) > 0 {
+		return fmt.Errorf("extension ranges are not allowed in %s", s)
+	}
+	if err := checkFeatures(s, targetMessage, m.options); err != nil {
+		return err
+	}
+	return checkLabelsRequired(s, m.fields)
+}
+
+// applyFile returns an error if the message or any of its options, fields, oneofs and nested declarations
+// use constructs which are not valid for the syntax.
+func (m *message) applyFile(ctx *fileContext) error {
+
+	err := m.syntaxError(ctx.syntax)
+	for _, es := range [][]any{toAny(m.reserved), toAny(m.fields), toAny(m.oneofs), toAny(m.enums), toAny(m.messages), toAny(m.extends)} {
+		if err == nil {
+			err = applyElements(ctx, es)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("message %s: %w", m.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the message, its fields and its nested declarations.
+func (m *message) validate(v *validator) {
+	v.within("message", m.name, func() {
+		v.report(checkIdent(m.name))
+		v.report(m.syntaxError(v.syntax))
+		v.reportAll(fieldErrors(m.allFields(), m.reserved, m.extensions))
+		s := scope{}
+		s.declareTypes(v, m.enums, m.messages)
+		s.declareMembers(v, m.allFields(), m.oneofs)
+		for _, es := range [][]any{toAny(m.reserved), toAny(m.fields), toAny(m.oneofs), toAny(m.enums), toAny(m.messages), toAny(m.extends)} {
+			validateElements(v, es)
+		}
+	})
+}
+
 // Render generates a formatted representation of the message and writes it to the provided Output.
-// It writes each field with proper indentation, utilizing the Output interface for structured rendering.
+// It writes each field and nested declaration with proper indentation, utilizing the Output interface for structured rendering.
 // Returns an error if any part of the rendering or writing process fails.
 func (m *message) Render(o protogen.Output) error {
 
-	var err error
+	err := m.checkFields()
+	if err != nil {
+		return err
+	}
 
-	err = o.WriteLines(fmt.Sprintf("message %s {", m.name))
+	err = writeLeadingComments(o, m.comments)
+	if err != nil {
+		return err
+	}
+
+	err = o.WriteLines(fmt.Sprintf("message %s {%s", m.name, trailingComment(m.comments)))
 
 	if err != nil {
 		return err
 	}
 
-	for _, f := range m.fields {
+	io := newBlockOutput(o)
 
-		io := protogen.NewIndentingOutput(o, 2)
-		err = f.Render(io)
+	for _, r := range [][]protogen.Renderer{toRenderers(m.options), toRenderers(m.reserved), toRenderers(m.fields), toRenderers(m.oneofs), toRenderers(m.extensions), toRenderers(m.enums), toRenderers(m.messages), toRenderers(m.extends)} {
+		err = renderElements(io, r)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = writeClosingComments(io, m.comments)
+	if err != nil {
+		return err
+	}
+
 	return o.WriteLines("}", "")
 }
 
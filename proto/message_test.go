@@ -0,0 +1,376 @@
+package proto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/activatedio/protogen"
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Render(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	cases := []struct {
+		name    string
+		arrange func() proto.Message
+		assert  func(got []byte, err error)
+	}{
+		{
+			name: "nested",
+			arrange: func() proto.Message {
+
+				order := proto.NewMessage("Order")
+				lineItem := proto.NewMessage("LineItem")
+				status := proto.NewEnum("Status")
+				option := proto.NewMessage("Option")
+
+				order.AddMessages(lineItem).AddEnums(status)
+				lineItem.AddMessages(option)
+
+				a.Equal("Order.LineItem", lineItem.GetTypeName())
+				a.Equal("Order.LineItem.Option", option.GetTypeName())
+				a.Equal("Order.Status", status.GetTypeName())
+
+				status.AddValues(
+					proto.NewEnumValue("STATUS_UNSPECIFIED", 0),
+				)
+				lineItem.AddFields(
+					proto.NewField("sku", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+					proto.NewField("options", proto.FieldParams{
+						FieldType: option,
+						Number:    2,
+						Repeated:  true,
+					}),
+				)
+				option.AddFields(
+					proto.NewField("name", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+				)
+
+				return order.AddFields(
+					proto.NewField("items", proto.FieldParams{
+						FieldType: lineItem,
+						Number:    1,
+						Repeated:  true,
+					}),
+					proto.NewField("status", proto.FieldParams{
+						FieldType: status,
+						Number:    2,
+					}),
+				).AddMessages(proto.NewMessage("Empty"))
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`message Order {
+  repeated Order.LineItem items = 1;
+  Order.Status status = 2;
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+  }
+
+  message LineItem {
+    string sku = 1;
+    repeated Order.LineItem.Option options = 2;
+    message Option {
+      string name = 1;
+    }
+  }
+
+  message Empty {
+  }
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "oneofs",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Event").AddFields(
+					proto.NewField("id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+				).AddOneofs(
+					proto.NewOneof("payload").AddOptions(
+						proto.NewOption("api.required", proto.NewBoolConstant(true)),
+					).AddFields(
+						proto.NewField("created", proto.FieldParams{
+							FieldType: proto.NewMessage("Created"),
+							Number:    2,
+						}),
+						proto.NewField("deleted", proto.FieldParams{
+							FieldType: proto.NewMessage("Deleted"),
+							Number:    3,
+						}),
+					),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`message Event {
+  string id = 1;
+  oneof payload {
+    option (api.required) = true;
+    Created created = 2;
+    Deleted deleted = 3;
+  }
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "oneof number collision",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Event").AddFields(
+					proto.NewField("id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+				).AddOneofs(
+					proto.NewOneof("payload").AddFields(
+						proto.NewField("created", proto.FieldParams{
+							FieldType: proto.NewMessage("Created"),
+							Number:    1,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Event: field created reuses number 1 of field id")
+			},
+		},
+		{
+			name: "optional oneof field",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Event").AddOneofs(
+					proto.NewOneof("payload").AddFields(
+						proto.NewField("created", proto.FieldParams{
+							FieldType: proto.NewMessage("Created"),
+							Number:    1,
+							Label:     proto.LabelOptional,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "oneof payload: field created cannot be optional")
+			},
+		},
+		{
+			name: "map oneof field",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Event").AddOneofs(
+					proto.NewOneof("payload").AddFields(
+						proto.NewField("tags", proto.FieldParams{
+							KeyType:   proto.String,
+							FieldType: proto.String,
+							Number:    1,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "oneof payload: field tags cannot be a map")
+			},
+		},
+		{
+			name: "duplicate field name",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Event").AddFields(
+					proto.NewField("id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+					proto.NewField("id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    2,
+					}),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Event: field name id is used more than once")
+			},
+		},
+		{
+			name: "reserved",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Order").AddReserved(
+					proto.NewReservedNumbers(2, 15),
+					proto.NewReservedRange(9, 11),
+					proto.NewReservedRangeToMax(1000),
+					proto.NewReservedNames("legacy_id", "old_total"),
+				).AddFields(
+					proto.NewField("id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`message Order {
+  reserved 2, 15;
+  reserved 9 to 11;
+  reserved 1000 to max;
+  reserved "legacy_id", "old_total";
+  string id = 1;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "reserved number collision",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Order").AddReserved(
+					proto.NewReservedRangeToMax(1000),
+				).AddOneofs(
+					proto.NewOneof("total").AddFields(
+						proto.NewField("amount", proto.FieldParams{
+							FieldType: proto.Int64,
+							Number:    5000,
+						}),
+					),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Order: field amount uses reserved number 5000")
+			},
+		},
+		{
+			name: "reserved name collision",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Order").AddReserved(
+					proto.NewReservedNames("legacy_id"),
+				).AddFields(
+					proto.NewField("legacy_id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}),
+				)
+			},
+			assert: func(_ []byte, err error) {
+				r.EqualError(err, "message Order: field legacy_id uses a reserved name")
+			},
+		},
+		{
+			name: "reserved names after writing an editions file",
+			arrange: func() proto.Message {
+				m := proto.NewMessage("Search").AddReserved(
+					proto.NewReservedNames("legacy"),
+				)
+				f := proto.NewFile("unit", proto.FileParams{Syntax: proto.SyntaxEditions}).AddMessages(m)
+				r.NoError(f.Write(&bytes.Buffer{}))
+				return m
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`message Search {
+  reserved "legacy";
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "rendered on its own after writing a file",
+			arrange: func() proto.Message {
+				amount := proto.NewMessage("Amount")
+				proto.NewFile("common.money", proto.FileParams{Path: "common/money.proto"}).AddMessages(amount)
+				order := proto.NewMessage("Order").AddFields(
+					proto.NewField("total", proto.FieldParams{
+						FieldType: amount,
+						Number:    1,
+					}),
+				)
+				f := proto.NewFile("orders").AddMessages(order)
+				buf := &bytes.Buffer{}
+				r.NoError(f.Write(buf))
+				a.Contains(buf.String(), "common.money.Amount total = 1;")
+				return order
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`message Order {
+  Amount total = 1;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "block comments containing the end marker",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Order").SetComments(proto.Comments{
+					Leading:  "Matches paths like a/*/b",
+					Trailing: "see */ below",
+					Style:    proto.CommentStyleBlock,
+				}).AddFields(
+					proto.NewField("id", proto.FieldParams{
+						FieldType: proto.String,
+						Number:    1,
+					}).SetComments(proto.Comments{
+						Leading: "Unique id",
+						Style:   proto.CommentStyleBlock,
+					}),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`// Matches paths like a/*/b
+message Order { // see */ below
+  /* Unique id */
+  string id = 1;
+}
+
+`, string(got))
+			},
+		},
+		{
+			name: "closing comments",
+			arrange: func() proto.Message {
+				return proto.NewMessage("Order").SetComments(proto.Comments{
+					Closing: []string{"Totals were removed", "Keep this last\nfor now"},
+					Style:   proto.CommentStyleBlock,
+				}).AddFields(
+					proto.NewField("id", proto.FieldParams{FieldType: proto.String, Number: 1}),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`message Order {
+  string id = 1;
+  /* Totals were removed */
+
+  /*
+   * Keep this last
+   * for now
+   */
+}
+
+`, string(got))
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			buf := &bytes.Buffer{}
+			unit := tt.arrange()
+			err := unit.Render(protogen.NewWriterOutput(buf))
+			tt.assert(buf.Bytes(), err)
+		})
+	}
+}
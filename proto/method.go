@@ -9,15 +9,25 @@ import (
 // Method defines an interface for rendering structured RPC methods and supports adding options to the method configuration.
 type Method interface {
 	protogen.Renderer
+	SetComments(c Comments) Method
 	AddOptions(o ...Option) Method
 }
 
 // method represents a gRPC method definition with its name, request type, response type, and related options.
 type method struct {
-	name         string
-	requestName  string
-	responseName string
-	options      []Option
+	name            string
+	requestType     typeUse
+	responseType    typeUse
+	clientStreaming bool
+	serverStreaming bool
+	comments        Comments
+	options         []Option
+}
+
+// SetComments sets the comments written with the method and returns the updated Method.
+func (m *method) SetComments(c Comments) Method {
+	m.comments = c
+	return m
 }
 
 // AddOptions appends one or more Option instances to the method's options and returns the updated Method.
@@ -26,16 +36,63 @@ func (m *method) AddOptions(o ...Option) Method {
 	return m
 }
 
+// applyFile returns an error if the options of the method are not valid for the syntax, and records the imports its
+// request and response types need.
+func (m *method) applyFile(ctx *fileContext) error {
+	err := checkFeatures(ctx.syntax, targetMethod, m.options)
+	if err == nil {
+		err = m.requestType.use(ctx)
+	}
+	if err == nil {
+		err = m.responseType.use(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("method %s: %w", m.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the name, request and response types and options of the method.
+func (m *method) validate(v *validator) {
+	v.within("method", m.name, func() {
+		v.report(checkIdent(m.name))
+		if err := checkMessageRef(m.requestType.ref); err != nil {
+			v.report(fmt.Errorf("request: %w", err))
+		}
+		if err := checkMessageRef(m.responseType.ref); err != nil {
+			v.report(fmt.Errorf("response: %w", err))
+		}
+		v.report(checkFeatures(v.syntax, targetMethod, m.options))
+	})
+}
+
 // Render generates the RPC method definition with its name, request type, and response type in the provided output.
 // It also processes and renders each associated option, handling errors from writing operations accordingly.
 func (m *method) Render(o protogen.Output) error {
 
-	err := o.WriteLines(fmt.Sprintf("rpc %s (%s) returns (%s) {", m.name, m.requestName, m.responseName))
+	err := checkMessageRef(m.requestType.ref)
+	if err != nil {
+		return fmt.Errorf("method %s: request: %w", m.name, err)
+	}
+
+	err = checkMessageRef(m.responseType.ref)
+	if err != nil {
+		return fmt.Errorf("method %s: response: %w", m.name, err)
+	}
+
+	err = writeLeadingComments(o, m.comments)
+	if err != nil {
+		return err
+	}
+
+	err = o.WriteLines(fmt.Sprintf("rpc %s (%s) returns (%s) {%s", m.name,
+		streamType(m.requestType.name(o), m.clientStreaming), streamType(m.responseType.name(o), m.serverStreaming),
+		trailingComment(m.comments)))
 	if err != nil {
 		return err
 	}
 
-	io := protogen.NewIndentingOutput(o, 2)
+	io := indent(o)
 
 	for _, opt := range m.options {
 		err = opt.Render(io)
@@ -44,21 +101,39 @@ func (m *method) Render(o protogen.Output) error {
 		}
 	}
 
+	err = writeClosingComments(io, m.comments)
+	if err != nil {
+		return err
+	}
+
 	return o.WriteLines("}")
 }
 
-// MethodParams defines the request and response names for a method in a proto service.
+// streamType returns the message type of a request or response, prefixed with stream when streaming.
+func streamType(name string, streaming bool) string {
+	if streaming {
+		return "stream " + name
+	}
+	return name
+}
+
+// MethodParams defines the request and response message types for a method in a proto service,
+// and whether the client sends a stream of requests or the server returns a stream of responses.
 type MethodParams struct {
-	RequestName  string
-	ResponseName string
+	RequestType     TypeRef
+	ResponseType    TypeRef
+	ClientStreaming bool
+	ServerStreaming bool
 }
 
 // NewMethod creates a new Method instance with the provided name and MethodParams,
 // which define the request and response types for the RPC method.
 func NewMethod(name string, params MethodParams) Method {
 	return &method{
-		name:         name,
-		requestName:  params.RequestName,
-		responseName: params.ResponseName,
+		name:            name,
+		requestType:     typeUse{ref: params.RequestType},
+		responseType:    typeUse{ref: params.ResponseType},
+		clientStreaming: params.ClientStreaming,
+		serverStreaming: params.ServerStreaming,
 	}
 }
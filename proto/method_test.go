@@ -25,18 +25,41 @@ func TestMethod_Render(t *testing.T) {
 			name: "simple",
 			arrange: func() proto.Method {
 				return proto.NewMethod("method1", proto.MethodParams{
-					RequestName:  "request1",
-					ResponseName: "response1",
+					RequestType:  proto.NewMessage("request1"),
+					ResponseType: proto.NewMessage("response1"),
 				})
 			},
 			expected: "rpc method1 (request1) returns (response1) {\n}\n",
 		},
+		{
+			name: "server streaming",
+			arrange: func() proto.Method {
+				return proto.NewMethod("method1", proto.MethodParams{
+					RequestType:     proto.NewMessage("request1"),
+					ResponseType:    proto.NewMessage("response1"),
+					ServerStreaming: true,
+				})
+			},
+			expected: "rpc method1 (request1) returns (stream response1) {\n}\n",
+		},
+		{
+			name: "bidirectional streaming",
+			arrange: func() proto.Method {
+				return proto.NewMethod("method1", proto.MethodParams{
+					RequestType:     proto.NewMessage("request1"),
+					ResponseType:    proto.NewMessage("response1"),
+					ClientStreaming: true,
+					ServerStreaming: true,
+				})
+			},
+			expected: "rpc method1 (stream request1) returns (stream response1) {\n}\n",
+		},
 		{
 			name: "with options",
 			arrange: func() proto.Method {
 				return proto.NewMethod("method1", proto.MethodParams{
-					RequestName:  "request1",
-					ResponseName: "response1",
+					RequestType:  proto.NewMessage("request1"),
+					ResponseType: proto.NewMessage("response1"),
 				}).AddOptions(
 					proto.NewOption("option1", proto.NewStringConstant("value1")),
 					proto.NewOption("option2",
@@ -68,3 +91,43 @@ func TestMethod_Render(t *testing.T) {
 		})
 	}
 }
+
+func TestMethod_Render_Invalid(t *testing.T) {
+
+	r := require.New(t)
+
+	cases := []struct {
+		name     string
+		arrange  func() proto.Method
+		expected string
+	}{
+		{
+			name: "scalar request",
+			arrange: func() proto.Method {
+				return proto.NewMethod("method1", proto.MethodParams{
+					RequestType:  proto.String,
+					ResponseType: proto.NewMessage("response1"),
+				})
+			},
+			expected: "method method1: request: string is not a message type",
+		},
+		{
+			name: "enum response",
+			arrange: func() proto.Method {
+				return proto.NewMethod("method1", proto.MethodParams{
+					RequestType:  proto.NewMessage("request1"),
+					ResponseType: proto.NewEnum("Status"),
+				})
+			},
+			expected: "method method1: response: Status is not a message type",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			unit := tt.arrange()
+			err := unit.Render(protogen.NewWriterOutput(&bytes.Buffer{}))
+			r.EqualError(err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/activatedio/protogen"
+)
+
+// Oneof represents a group of fields within a message of which at most one may be set at a time.
+type Oneof interface {
+	protogen.Renderer
+	GetName() string
+	GetFields() []Field
+	SetComments(Comments) Oneof
+	AddFields(...Field) Oneof
+	AddOptions(...Option) Oneof
+}
+
+// oneof represents a named oneof group with its fields and options.
+type oneof struct {
+	name     string
+	comments Comments
+	fields   []Field
+	options  []Option
+}
+
+// GetName returns the name of the oneof.
+func (o *oneof) GetName() string {
+	return o.name
+}
+
+// GetFields returns the fields of the oneof.
+func (o *oneof) GetFields() []Field {
+	return o.fields
+}
+
+// SetComments sets the comments written with the oneof and returns the updated Oneof.
+func (o *oneof) SetComments(c Comments) Oneof {
+	o.comments = c
+	return o
+}
+
+// AddFields appends one or more Field elements to the oneof and returns the updated Oneof.
+func (o *oneof) AddFields(f ...Field) Oneof {
+	o.fields = append(o.fields, f...)
+	return o
+}
+
+// AddOptions appends one or more Option instances to the oneof and returns the updated Oneof.
+func (o *oneof) AddOptions(opts ...Option) Oneof {
+	o.options = append(o.options, opts...)
+	return o
+}
+
+// fieldErrors returns an error for each field of the oneof which has a label or is a map field, since oneof fields
+// are always singular.
+func (o *oneof) fieldErrors() []error {
+	var errs []error
+	for _, f := range o.fields {
+		switch {
+		case f.GetLabel() != LabelImplicit:
+			errs = append(errs, fmt.Errorf("field %s cannot be %s", f.GetName(), f.GetLabel()))
+		case isMapField(f):
+			errs = append(errs, fmt.Errorf("field %s cannot be a map", f.GetName()))
+		}
+	}
+	return errs
+}
+
+// isMapField reports whether the field is a map field.
+func isMapField(f Field) bool {
+	ff, ok := f.(*field)
+	return ok && ff.keyType != ""
+}
+
+// checkFields returns an error for the first field of the oneof which has a label or is a map field.
+func (o *oneof) checkFields() error {
+	if errs := o.fieldErrors(); len(errs) > 0 {
+		return fmt.Errorf("oneof %s: %w", o.name, errs[0])
+	}
+	return nil
+}
+
+// applyFile returns an error if the options or fields of the oneof are not valid for the syntax.
+func (o *oneof) applyFile(ctx *fileContext) error {
+	err := checkFeatures(ctx.syntax, targetOneof, o.options)
+	if err == nil {
+		err = applyElements(ctx, o.fields)
+	}
+	if err != nil {
+		return fmt.Errorf("oneof %s: %w", o.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the oneof and its fields.
+func (o *oneof) validate(v *validator) {
+	v.within("oneof", o.name, func() {
+		v.report(checkIdent(o.name))
+		if len(o.fields) == 0 {
+			v.report(errors.New("oneofs must have at least one field"))
+		}
+		v.reportAll(o.fieldErrors())
+		v.report(checkFeatures(v.syntax, targetOneof, o.options))
+		validateElements(v, o.fields)
+	})
+}
+
+// Render writes the oneof block, including its options and fields, to the provided Output.
+func (o *oneof) Render(out protogen.Output) error {
+
+	err := o.checkFields()
+	if err != nil {
+		return err
+	}
+
+	err = writeLeadingComments(out, o.comments)
+	if err != nil {
+		return err
+	}
+
+	err = out.WriteLines(fmt.Sprintf("oneof %s {%s", o.name, trailingComment(o.comments)))
+	if err != nil {
+		return err
+	}
+
+	io := newBlockOutput(out)
+
+	for _, r := range [][]protogen.Renderer{toRenderers(o.options), toRenderers(o.fields)} {
+		err = renderElements(io, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writeClosingComments(io, o.comments)
+	if err != nil {
+		return err
+	}
+
+	return out.WriteLines("}")
+}
+
+// NewOneof creates a new Oneof with the specified name.
+func NewOneof(name string) Oneof {
+	return &oneof{
+		name: name,
+	}
+}
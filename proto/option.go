@@ -10,6 +10,9 @@ import (
 // Option represents a renderable configuration element that extends the protogen.Renderer interface.
 type Option interface {
 	protogen.Renderer
+	GetName() string
+	GetValue() Constant
+	SetComments(Comments) Option
 }
 
 // option represents an implementation of the Option interface for rendering an option and its associated value.
@@ -17,22 +20,40 @@ type Option interface {
 type option struct {
 	name          string
 	constantValue Constant
+	comments      Comments
+}
+
+// GetName returns the name of the option.
+func (o *option) GetName() string {
+	return o.name
+}
+
+// GetValue returns the constant value of the option.
+func (o *option) GetValue() Constant {
+	return o.constantValue
+}
+
+// SetComments sets the comments written with the option statement and returns the updated Option.
+// Comments are not written when the option is rendered in bracketed form.
+func (o *option) SetComments(c Comments) Option {
+	o.comments = c
+	return o
 }
 
 // Render generates the textual representation of an option and writes it to the provided Output instance.
 // Returns an error if any stage of rendering or writing fails.
 func (o *option) Render(out protogen.Output) error {
-	err := out.StartLine()
+	err := writeLeadingComments(out, o.comments)
 	if err != nil {
 		return err
 	}
 
-	name := o.name
-	if strings.Contains(name, ".") {
-		name = fmt.Sprintf("(%s)", name)
+	err = out.StartLine()
+	if err != nil {
+		return err
 	}
 
-	err = out.Write(fmt.Sprintf("option %s = ", name))
+	err = out.Write(fmt.Sprintf("option %s = ", optionName(o.name)))
 	if err != nil {
 		return err
 	}
@@ -40,13 +61,44 @@ func (o *option) Render(out protogen.Output) error {
 	if err != nil {
 		return err
 	}
-	err = out.Write(";\n")
+	err = out.Write(fmt.Sprintf(";%s\n", trailingComment(o.comments)))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// optionName returns the name as it appears in an option statement, with custom (dotted) option names in parentheses.
+// Names which already contain parentheses, such as (validate.rules).string.min_len, and editions features, such as
+// features.field_presence, are used as given.
+func optionName(name string) string {
+	if strings.Contains(name, ".") && !strings.HasPrefix(name, "(") && !strings.HasPrefix(name, featuresPrefix) {
+		return fmt.Sprintf("(%s)", name)
+	}
+	return name
+}
+
+// compactOptions renders options in the bracketed form used after fields and enum values, such as
+// ` [deprecated = true]`. It returns an empty string when there are no options.
+func compactOptions(opts []Option) (string, error) {
+
+	if len(opts) == 0 {
+		return "", nil
+	}
+
+	parts := make([]string, 0, len(opts))
+
+	for _, opt := range opts {
+		v, err := renderToString(opt.GetValue())
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s = %s", optionName(opt.GetName()), v))
+	}
+
+	return fmt.Sprintf(" [%s]", strings.Join(parts, ", ")), nil
+}
+
 // NewOption creates a new Option with the specified name and associated Constant value.
 func NewOption(name string, constantValue Constant) Option {
 	return &option{
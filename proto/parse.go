@@ -0,0 +1,1372 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/activatedio/protogen/tfl"
+)
+
+// ParseError is returned by Parse when the source is not a valid proto file. Line and Column locate the problem,
+// counting from 1.
+type ParseError struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error returns the position and message of the error, such as orders.proto:3:10: expected ";", found "}".
+func (e *ParseError) Error() string {
+	pos := fmt.Sprintf("%d:%d", e.Line, e.Column)
+	if e.Path != "" {
+		pos = e.Path + ":" + pos
+	}
+	return pos + ": " + e.Message
+}
+
+// Parse reads a proto file written in the proto2, proto3 or editions grammar into a File with the path. Comments are
+// kept on the elements which support them. Types other than scalars are referred to as external types which are
+// written back with their name as written, including a leading dot, and can be resolved by a Project.
+func Parse(path string, r io.Reader) (File, error) {
+
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := newLexer(string(src)).tokens()
+	if err != nil {
+		return nil, withPath(err, path)
+	}
+
+	p := &parser{tokens: ts, path: path}
+
+	f, err := p.file()
+	if err != nil {
+		return nil, withPath(err, path)
+	}
+	return f, nil
+}
+
+// withPath sets the path of a ParseError.
+func withPath(err error, path string) error {
+	if pe, ok := err.(*ParseError); ok {
+		pe.Path = path
+	}
+	return err
+}
+
+// parser builds the elements of a file from its tokens.
+type parser struct {
+	tokens []token
+	pos    int
+	path   string
+	syntax Syntax
+}
+
+// peek returns the current token.
+func (p *parser) peek() *token {
+	return &p.tokens[p.pos]
+}
+
+// lookAhead returns the token n positions after the current token, or the final tokenEOF.
+func (p *parser) lookAhead(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+// next consumes and returns the current token. The final tokenEOF is never consumed.
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the current token is the identifier or symbol.
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenIdent || t.kind == tokenSymbol) && t.text == text
+}
+
+// errorf returns a ParseError at the position of the token.
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &ParseError{Line: t.line, Column: t.column, Message: fmt.Sprintf(format, args...)}
+}
+
+// expect consumes the current token if it is the identifier or symbol, and returns an error otherwise.
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.errorf(*p.peek(), "expected %q, found %s", text, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+// ident consumes an identifier.
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return "", p.errorf(*t, "expected identifier, found %s", t)
+	}
+	return p.next().text, nil
+}
+
+// fullIdent consumes a dotted identifier, optionally starting with a dot.
+func (p *parser) fullIdent() (string, error) {
+
+	sb := strings.Builder{}
+	if p.is(".") {
+		sb.WriteString(p.next().text)
+	}
+
+	for {
+		id, err := p.ident()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(id)
+		if !p.is(".") {
+			return sb.String(), nil
+		}
+		sb.WriteString(p.next().text)
+	}
+}
+
+// stringLit consumes one or more adjacent string literals and returns their joined content.
+func (p *parser) stringLit() (string, error) {
+	t := p.peek()
+	if t.kind != tokenString {
+		return "", p.errorf(*t, "expected string, found %s", t)
+	}
+	sb := strings.Builder{}
+	for p.peek().kind == tokenString {
+		sb.WriteString(p.next().text)
+	}
+	return sb.String(), nil
+}
+
+// intLit consumes an integer literal with an optional sign, checking it fits into the number of bits.
+func (p *parser) intLit(bits int) (int64, error) {
+
+	t := *p.peek()
+	sign := ""
+	if p.is("-") || p.is("+") {
+		sign = p.next().text
+	}
+
+	n := p.peek()
+	if n.kind != tokenInt {
+		return 0, p.errorf(*n, "expected integer, found %s", n)
+	}
+	p.next()
+
+	limit := uint64(1)<<(bits-1) - 1
+	if sign == "-" {
+		limit++
+	}
+
+	v, err := parseIntLit(n.text)
+	if err != nil || v > limit {
+		return 0, p.errorf(t, "invalid integer %s%s", sign, n.text)
+	}
+	if sign == "-" {
+		return -int64(v), nil
+	}
+	return int64(v), nil
+}
+
+// parseIntLit parses the text of an integer literal in the proto grammar, which is decimal, octal with a leading 0
+// or hexadecimal with a leading 0x. Go forms such as 0b11, 0o17 and 1_000 are not accepted.
+func parseIntLit(text string) (uint64, error) {
+	switch {
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X"):
+		return strconv.ParseUint(text[2:], 16, 64)
+	case len(text) > 1 && text[0] == '0':
+		return strconv.ParseUint(text[1:], 8, 64)
+	}
+	return strconv.ParseUint(text, 10, 64)
+}
+
+// number consumes a field or enum value number.
+func (p *parser) number() (int32, error) {
+	v, err := p.intLit(32)
+	return int32(v), err
+}
+
+// leading consumes the comments preceding the current token and returns them as the leading and detached comments
+// of the element starting with it. The comment block directly above the token is its leading comment, and earlier
+// blocks are detached. Consecutive line comments form a single block.
+func (p *parser) leading() Comments {
+
+	t := p.peek()
+	cs := t.comments
+	t.comments = nil
+
+	var blocks []comment
+	for _, c := range cs {
+		if n := len(blocks) - 1; n >= 0 && !c.block && !blocks[n].block && !blocks[n].trailing && blocks[n].endLine+1 == c.line {
+			blocks[n].text += "\n" + c.text
+			blocks[n].endLine = c.endLine
+			continue
+		}
+		blocks = append(blocks, c)
+	}
+
+	var res Comments
+	for n, b := range blocks {
+		if n == 0 {
+			res.Style = commentStyle(b)
+		}
+		if n == len(blocks)-1 && b.endLine+1 >= t.line {
+			res.Leading = b.text
+			continue
+		}
+		res.Detached = append(res.Detached, b.text)
+	}
+	return res
+}
+
+// trailing consumes the comment following the previous token on the same line and sets it as the trailing comment.
+func (p *parser) trailing(c *Comments) {
+
+	t := p.peek()
+	if len(t.comments) == 0 || !t.comments[0].trailing {
+		return
+	}
+
+	tc := t.comments[0]
+	t.comments = t.comments[1:]
+
+	c.Trailing = tc.text
+	if c.Leading == "" && len(c.Detached) == 0 {
+		c.Style = commentStyle(tc)
+	}
+}
+
+// commentStyle returns the style of the comment.
+func commentStyle(c comment) CommentStyle {
+	if c.block {
+		return CommentStyleBlock
+	}
+	return CommentStyleLine
+}
+
+// file parses the whole source.
+func (p *parser) file() (File, error) {
+
+	params := FileParams{Syntax: SyntaxProto2, Path: p.path}
+	c := p.leading()
+
+	switch {
+	case p.is("syntax"):
+		p.next()
+		s, err := p.assignedString()
+		if err != nil {
+			return nil, err
+		}
+		switch s {
+		case "proto2":
+		case "proto3":
+			params.Syntax = SyntaxProto3
+		default:
+			return nil, p.errorf(p.tokens[p.pos-2], "unknown syntax %q", s)
+		}
+	case p.is("edition"):
+		p.next()
+		s, err := p.assignedString()
+		if err != nil {
+			return nil, err
+		}
+		params.Syntax = SyntaxEditions
+		params.Edition = Edition(s)
+	}
+	p.syntax = params.Syntax
+	p.trailing(&c)
+
+	var (
+		packageName string
+		pc          Comments
+		imports     []Import
+		options     []Option
+		enums       []Enum
+		messages    []Message
+		extends     []Extend
+		services    []Service
+	)
+
+	for p.peek().kind != tokenEOF {
+
+		ec := p.leading()
+		var err error
+
+		switch {
+		case p.is(";"):
+			p.next()
+		case p.is("package"):
+			p.next()
+			packageName, err = p.fullIdent()
+			if err == nil {
+				err = p.expect(";")
+			}
+			p.trailing(&ec)
+			pc = ec
+		case p.is("import"):
+			var i Import
+			i, err = p.importStatement(ec)
+			imports = append(imports, i)
+		case p.is("option"):
+			var o Option
+			o, err = p.optionStatement(ec)
+			options = append(options, o)
+		case p.is("message"):
+			var m Message
+			m, err = p.message(ec)
+			messages = append(messages, m)
+		case p.is("enum"):
+			var e Enum
+			e, err = p.enum(ec)
+			enums = append(enums, e)
+		case p.is("extend"):
+			var e Extend
+			e, err = p.extend(ec)
+			extends = append(extends, e)
+		case p.is("service"):
+			var s Service
+			s, err = p.service(ec)
+			services = append(services, s)
+		default:
+			err = p.errorf(*p.peek(), "unexpected %s", p.peek())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.closing(&c)
+
+	return NewFile(packageName, params).
+		SetComments(c).
+		SetPackageComments(pc).
+		AddImports(imports...).
+		AddOptions(options...).
+		AddEnums(enums...).
+		AddMessages(messages...).
+		AddExtends(extends...).
+		AddServices(services...), nil
+}
+
+// assignedString consumes the rest of a syntax or edition statement and returns its value.
+func (p *parser) assignedString() (string, error) {
+	err := p.expect("=")
+	if err != nil {
+		return "", err
+	}
+	s, err := p.stringLit()
+	if err != nil {
+		return "", err
+	}
+	return s, p.expect(";")
+}
+
+// importStatement parses an import statement with its comments.
+func (p *parser) importStatement(c Comments) (Import, error) {
+
+	p.next()
+
+	modifier := ImportDefault
+	switch {
+	case p.is("public"):
+		p.next()
+		modifier = ImportPublic
+	case p.is("weak"):
+		p.next()
+		modifier = ImportWeak
+	}
+
+	path, err := p.stringLit()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect(";")
+	if err != nil {
+		return nil, err
+	}
+
+	p.trailing(&c)
+	return NewImport(path, modifier).SetComments(c), nil
+}
+
+// optionStatement parses an option statement with its comments.
+func (p *parser) optionStatement(c Comments) (Option, error) {
+
+	p.next()
+
+	o, err := p.option()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect(";")
+	if err != nil {
+		return nil, err
+	}
+
+	p.trailing(&c)
+	return o.SetComments(c), nil
+}
+
+// option parses the name and value of an option. Built-in editions features become typed feature options, while
+// extension features such as features.(pb.cpp).string_type and features unknown to this package are kept as options.
+func (p *parser) option() (Option, error) {
+
+	name, err := p.optionName()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect("=")
+	if err != nil {
+		return nil, err
+	}
+
+	t := *p.peek()
+	value, err := p.constant()
+	if err != nil {
+		return nil, err
+	}
+
+	if feature, ok := strings.CutPrefix(name, featuresPrefix); ok {
+		if _, ok := featureTargets[feature]; ok {
+			if t.kind != tokenIdent {
+				return nil, p.errorf(t, "feature %s must be set to an identifier", name)
+			}
+			return newFeature(feature, t.text), nil
+		}
+	}
+
+	return NewOption(name, value), nil
+}
+
+// optionName parses the name of an option. A custom option written as (pkg.name) is returned without parentheses,
+// since they are added again when it is written.
+func (p *parser) optionName() (string, error) {
+
+	sb := strings.Builder{}
+
+	for {
+		if p.is("(") {
+			p.next()
+			n, err := p.fullIdent()
+			if err != nil {
+				return "", err
+			}
+			err = p.expect(")")
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString("(" + n + ")")
+		} else {
+			n, err := p.ident()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(n)
+		}
+		if !p.is(".") {
+			break
+		}
+		sb.WriteString(p.next().text)
+	}
+
+	name := sb.String()
+	if inner := strings.TrimSuffix(strings.TrimPrefix(name, "("), ")"); len(inner) == len(name)-2 &&
+		!strings.ContainsAny(inner, "()") && strings.Contains(inner, ".") {
+		return inner, nil
+	}
+	return name, nil
+}
+
+// constant parses the value of an option.
+func (p *parser) constant() (Constant, error) {
+
+	t := *p.peek()
+
+	switch {
+	case t.kind == tokenString:
+		s, err := p.stringLit()
+		return NewStringConstant(s), err
+	case t.kind == tokenIdent && (t.text == "true" || t.text == "false"):
+		p.next()
+		return NewBoolConstant(t.text == "true"), nil
+	case t.kind == tokenIdent || p.is("."):
+		s, err := p.fullIdent()
+		return NewIdentifierConstant(s), err
+	case p.is("{"):
+		m, err := p.messageLiteral()
+		return NewMessageValueConstant(m), err
+	}
+
+	sign := ""
+	if p.is("-") || p.is("+") {
+		sign = p.next().text
+	}
+
+	n := *p.peek()
+	switch n.kind {
+	case tokenInt:
+		p.next()
+		v, err := parseIntLit(n.text)
+		if err != nil || (sign == "-" && v > 1<<63) {
+			return nil, p.errorf(t, "invalid integer %s%s", sign, n.text)
+		}
+		switch {
+		case sign == "-":
+			return NewIntConstant(int(-int64(v))), nil
+		case v > math.MaxInt64:
+			return NewUintConstant(v), nil
+		}
+		return NewIntConstant(int(v)), nil
+	case tokenFloat:
+		p.next()
+		v, err := strconv.ParseFloat(sign+n.text, 64)
+		if err != nil {
+			return nil, p.errorf(n, "invalid float %s%s", sign, n.text)
+		}
+		return NewFloatConstant(v), nil
+	case tokenIdent:
+		if sign != "" && (n.text == "inf" || n.text == "nan") {
+			p.next()
+			return NewIdentifierConstant(sign + n.text), nil
+		}
+	}
+
+	return nil, p.errorf(t, "expected constant, found %s", p.peek())
+}
+
+// messageLiteral parses a message value in the text format, delimited by braces or angle brackets. Its fields hold
+// scalars, strings, nested messages or lists of them, and nested messages may be written without a colon.
+func (p *parser) messageLiteral() (tfl.MessageValue, error) {
+
+	closing := "}"
+	if p.next().text == "<" {
+		closing = ">"
+	}
+	m := tfl.NewMessageValue()
+
+	for !p.is(closing) {
+
+		if p.peek().kind == tokenEOF {
+			return nil, p.errorf(*p.peek(), "message value is not closed")
+		}
+
+		name, err := p.textFieldName()
+		if err != nil {
+			return nil, err
+		}
+
+		var f tfl.Field
+		switch {
+		case p.is(":"):
+			p.next()
+			var v tfl.Value
+			v, err = p.textValue()
+			f = tfl.NewField(name, v)
+		case p.is("{") || p.is("<"):
+			var v tfl.MessageValue
+			v, err = p.messageLiteral()
+			f = tfl.NewMessageField(name, v)
+		default:
+			err = p.errorf(*p.peek(), "expected \":\" or message value, found %s", p.peek())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case p.is(";"):
+			p.next()
+			f = f.EndSemicolon()
+		case p.is(","):
+			p.next()
+			f = f.EndComma()
+		}
+		m.AddFields(f)
+	}
+
+	p.next()
+	return m, nil
+}
+
+// textFieldName parses the name of a field in a message value. Extensions and the type URLs of Any values are
+// written in square brackets, such as [pkg.ext] or [type.googleapis.com/pkg.Msg].
+func (p *parser) textFieldName() (string, error) {
+
+	if !p.is("[") {
+		return p.ident()
+	}
+	p.next()
+
+	sb := strings.Builder{}
+	sb.WriteString("[")
+	for {
+		n, err := p.fullIdent()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(n)
+		if !p.is("/") {
+			break
+		}
+		sb.WriteString(p.next().text)
+	}
+	sb.WriteString("]")
+
+	return sb.String(), p.expect("]")
+}
+
+// textValue parses the value of a field in a message value after its colon.
+func (p *parser) textValue() (tfl.Value, error) {
+
+	t := *p.peek()
+
+	switch {
+	case t.kind == tokenString:
+		s, err := p.stringLit()
+		return tfl.NewStringValue(s), err
+	case t.kind == tokenIdent:
+		p.next()
+		return tfl.NewScalarValue(t.text), nil
+	case p.is("{") || p.is("<"):
+		return p.messageLiteral()
+	case p.is("["):
+		return p.textList()
+	}
+
+	sign := ""
+	if p.is("-") {
+		sign = p.next().text
+	}
+
+	n := *p.peek()
+	if n.kind == tokenInt || n.kind == tokenFloat || (sign != "" && n.kind == tokenIdent) {
+		p.next()
+		return tfl.NewScalarValue(sign + n.text), nil
+	}
+
+	return nil, p.errorf(t, "expected value, found %s", p.peek())
+}
+
+// textList parses the values of a repeated field in a message value, written in square brackets.
+func (p *parser) textList() (tfl.Value, error) {
+
+	p.next()
+
+	var vs []tfl.Value
+	for !p.is("]") {
+		if len(vs) > 0 {
+			err := p.expect(",")
+			if err != nil {
+				return nil, err
+			}
+		}
+		v, err := p.textValue()
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+
+	p.next()
+	return tfl.NewListValue(vs...), nil
+}
+
+// compactOptions parses the bracketed options of a field or enum value, if any.
+func (p *parser) compactOptions() ([]Option, error) {
+
+	if !p.is("[") {
+		return nil, nil
+	}
+	p.next()
+
+	var opts []Option
+	for {
+		o, err := p.option()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, o)
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+
+	return opts, p.expect("]")
+}
+
+// declares reports whether the current token is the keyword starting a declaration or statement, rather than the
+// type of a field or the name of an enum value, as in message message { message message = 1; }. A field is written
+// as its type, its name and "=", and an enum value as its name and "=". Statements starting with option are always
+// options, since a field of type option is written the same way.
+func (p *parser) declares(keyword string) bool {
+	assigns := func(t token) bool {
+		return t.kind == tokenSymbol && t.text == "="
+	}
+	if !p.is(keyword) || assigns(p.lookAhead(1)) {
+		return false
+	}
+	return keyword == "option" || p.lookAhead(1).kind != tokenIdent || !assigns(p.lookAhead(2))
+}
+
+// openBlock consumes the opening brace of a block and sets the comment following it as the trailing comment.
+func (p *parser) openBlock(c *Comments) error {
+	err := p.expect("{")
+	if err == nil {
+		p.trailing(c)
+	}
+	return err
+}
+
+// closeBlock consumes the closing brace of a block, reporting unterminated blocks at the end of the source. Comments
+// before the brace become closing comments of the block.
+func (p *parser) closeBlock(kind, name string, c *Comments) error {
+	p.closing(c)
+	if p.peek().kind == tokenEOF {
+		return p.errorf(*p.peek(), "%s %s is not closed", kind, name)
+	}
+	return p.expect("}")
+}
+
+// closing consumes the comments before the closing brace of a block or the end of the source and adds them to the
+// closing comments. Their style is used if there are no other comments.
+func (p *parser) closing(c *Comments) {
+
+	cc := p.leading()
+	if cc.Leading != "" {
+		cc.Detached = append(cc.Detached, cc.Leading)
+	}
+	if len(cc.Detached) == 0 {
+		return
+	}
+
+	if c.Leading == "" && c.Trailing == "" && len(c.Detached) == 0 && len(c.Closing) == 0 {
+		c.Style = cc.Style
+	}
+	c.Closing = append(c.Closing, cc.Detached...)
+}
+
+// message parses a message declaration.
+func (p *parser) message(c Comments) (Message, error) {
+
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	m := NewMessage(name)
+
+	for !p.is("}") && p.peek().kind != tokenEOF {
+
+		ec := p.leading()
+
+		switch {
+		case p.is("}"):
+		case p.is(";"):
+			p.next()
+		case p.declares("option"):
+			var o Option
+			o, err = p.optionStatement(ec)
+			m.AddOptions(o)
+		case p.declares("message"):
+			var nm Message
+			nm, err = p.message(ec)
+			m.AddMessages(nm)
+		case p.declares("enum"):
+			var e Enum
+			e, err = p.enum(ec)
+			m.AddEnums(e)
+		case p.declares("extend"):
+			var e Extend
+			e, err = p.extend(ec)
+			m.AddExtends(e)
+		case p.declares("oneof"):
+			var o Oneof
+			o, err = p.oneof(ec)
+			m.AddOneofs(o)
+		case p.declares("reserved"):
+			var r Reserved
+			r, err = p.reserved()
+			m.AddReserved(r)
+		case p.declares("extensions"):
+			var es []ExtensionRange
+			es, err = p.extensions()
+			m.AddExtensionRanges(es...)
+		default:
+			var f Field
+			f, err = p.field(ec)
+			m.AddFields(f)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.closeBlock("message", name, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.SetComments(c), nil
+}
+
+// label consumes the label of a field, if any.
+func (p *parser) label() FieldLabel {
+	for _, l := range []FieldLabel{LabelOptional, LabelRepeated, LabelRequired} {
+		if next := p.lookAhead(1); p.is(l.String()) && (next.kind == tokenIdent || next.text == ".") {
+			p.next()
+			return l
+		}
+	}
+	return LabelImplicit
+}
+
+// typeRef parses the type of a field or method.
+func (p *parser) typeRef() (TypeRef, error) {
+	name, err := p.fullIdent()
+	if err != nil {
+		return nil, err
+	}
+	if scalarTypes[ScalarType(name)] {
+		return ScalarType(name), nil
+	}
+	return newSourceType(name), nil
+}
+
+// field parses a field, map field or group.
+func (p *parser) field(c Comments) (Field, error) {
+
+	label := p.label()
+
+	if p.is("group") && p.lookAhead(1).kind == tokenIdent {
+		return p.group(c, label)
+	}
+
+	params := FieldParams{Label: label}
+
+	if p.is("map") && p.lookAhead(1).text == "<" {
+		p.next()
+		p.next()
+		t := *p.peek()
+		key, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		k, ok := key.(ScalarType)
+		if !ok {
+			return nil, p.errorf(t, "map key type %s must be an integral or string type", typeRefName(key))
+		}
+		params.KeyType = k
+		err = p.expect(",")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := p.typeRef()
+	if err != nil {
+		return nil, err
+	}
+	params.FieldType = t
+
+	if params.KeyType != "" {
+		err = p.expect(">")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect("=")
+	if err != nil {
+		return nil, err
+	}
+	params.Number, err = p.number()
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := p.compactOptions()
+	if err != nil {
+		return nil, err
+	}
+	var rest []Option
+	for _, o := range opts {
+		if o.GetName() == "default" {
+			params.Default = o.GetValue()
+		} else {
+			rest = append(rest, o)
+		}
+	}
+
+	err = p.expect(";")
+	if err != nil {
+		return nil, err
+	}
+	p.trailing(&c)
+
+	return NewField(name, params).AddOptions(rest...).SetComments(c), nil
+}
+
+// group parses a proto2 group, whose body may only hold fields.
+func (p *parser) group(c Comments, label FieldLabel) (Field, error) {
+
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect("=")
+	if err != nil {
+		return nil, err
+	}
+	number, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := p.compactOptions()
+	if err != nil {
+		return nil, err
+	}
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := p.fields("group", name, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGroup(name, GroupParams{Number: number, Label: label}).
+		AddFields(fs...).
+		AddOptions(opts...).
+		SetComments(c), nil
+}
+
+// fields parses the fields of a group or extend block up to and including its closing brace, adding the comments before
+// the brace to the closing comments of the block.
+func (p *parser) fields(kind, name string, bc *Comments) ([]Field, error) {
+
+	var fs []Field
+
+	for !p.is("}") && p.peek().kind != tokenEOF {
+		c := p.leading()
+		switch {
+		case p.is("}"):
+		case p.is(";"):
+			p.next()
+		default:
+			f, err := p.field(c)
+			if err != nil {
+				return nil, err
+			}
+			fs = append(fs, f)
+		}
+	}
+
+	return fs, p.closeBlock(kind, name, bc)
+}
+
+// oneof parses a oneof and its fields.
+func (p *parser) oneof(c Comments) (Oneof, error) {
+
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	o := NewOneof(name)
+
+	for !p.is("}") && p.peek().kind != tokenEOF {
+
+		ec := p.leading()
+
+		switch {
+		case p.is("}"):
+		case p.is(";"):
+			p.next()
+		case p.declares("option"):
+			var opt Option
+			opt, err = p.optionStatement(ec)
+			o.AddOptions(opt)
+		default:
+			var f Field
+			f, err = p.field(ec)
+			o.AddFields(f)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.closeBlock("oneof", name, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.SetComments(c), nil
+}
+
+// numberRanges parses the comma separated numbers and ranges of a reserved or extensions statement.
+func (p *parser) numberRanges() ([]numberRange, error) {
+
+	var rs []numberRange
+
+	for {
+		start, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		r := numberRange{start: start, end: start}
+		if p.is("to") {
+			p.next()
+			if p.is("max") {
+				p.next()
+				r.toMax = true
+			} else {
+				r.end, err = p.number()
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		rs = append(rs, r)
+		if !p.is(",") {
+			return rs, nil
+		}
+		p.next()
+	}
+}
+
+// reserved parses a reserved statement holding either numbers and ranges or names.
+func (p *parser) reserved() (Reserved, error) {
+
+	p.next()
+
+	t := p.peek()
+	if t.kind == tokenString || (t.kind == tokenIdent && p.syntax == SyntaxEditions) {
+		var names []string
+		for {
+			var name string
+			var err error
+			if p.peek().kind == tokenString {
+				name, err = p.stringLit()
+			} else {
+				name, err = p.ident()
+			}
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+		return NewReservedNames(names...), p.expect(";")
+	}
+
+	rs, err := p.numberRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reserved{}
+	for _, rg := range rs {
+		if !rg.toMax && rg.start == rg.end {
+			r.numbers = append(r.numbers, rg.start)
+		} else {
+			r.ranges = append(r.ranges, rg)
+		}
+	}
+	return r, p.expect(";")
+}
+
+// extensions parses an extensions statement into an ExtensionRange for each range.
+func (p *parser) extensions() ([]ExtensionRange, error) {
+
+	p.next()
+
+	rs, err := p.numberRanges()
+	if err != nil {
+		return nil, err
+	}
+
+	es := make([]ExtensionRange, 0, len(rs))
+	for _, rg := range rs {
+		es = append(es, &extensionRange{numberRange: rg})
+	}
+	return es, p.expect(";")
+}
+
+// enum parses an enum declaration.
+func (p *parser) enum(c Comments) (Enum, error) {
+
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewEnum(name)
+
+	for !p.is("}") && p.peek().kind != tokenEOF {
+
+		ec := p.leading()
+
+		switch {
+		case p.is("}"):
+		case p.is(";"):
+			p.next()
+		case p.declares("option"):
+			var o Option
+			o, err = p.optionStatement(ec)
+			e.AddOptions(o)
+		case p.declares("reserved"):
+			var r Reserved
+			r, err = p.reserved()
+			e.AddReserved(r)
+		default:
+			var v EnumValue
+			v, err = p.enumValue(ec)
+			e.AddValues(v)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.closeBlock("enum", name, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.SetComments(c), nil
+}
+
+// enumValue parses an enum value.
+func (p *parser) enumValue(c Comments) (EnumValue, error) {
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect("=")
+	if err != nil {
+		return nil, err
+	}
+	number, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := p.compactOptions()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect(";")
+	if err != nil {
+		return nil, err
+	}
+	p.trailing(&c)
+
+	return NewEnumValue(name, number).AddOptions(opts...).SetComments(c), nil
+}
+
+// extend parses an extend block.
+func (p *parser) extend(c Comments) (Extend, error) {
+
+	p.next()
+
+	target, err := p.typeRef()
+	if err != nil {
+		return nil, err
+	}
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := p.fields("extend", typeRefName(target), &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewExtend(target).AddFields(fs...).SetComments(c), nil
+}
+
+// service parses a service declaration.
+func (p *parser) service(c Comments) (Service, error) {
+
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewService(name)
+
+	for !p.is("}") && p.peek().kind != tokenEOF {
+
+		ec := p.leading()
+
+		switch {
+		case p.is("}"):
+		case p.is(";"):
+			p.next()
+		case p.is("option"):
+			var o Option
+			o, err = p.optionStatement(ec)
+			s.AddOptions(o)
+		case p.is("rpc"):
+			var m Method
+			m, err = p.method(ec)
+			s.AddMethods(m)
+		default:
+			err = p.errorf(*p.peek(), "unexpected %s", p.peek())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.closeBlock("service", name, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SetComments(c), nil
+}
+
+// methodType parses the parenthesized request or response type of a method and whether it is streamed.
+func (p *parser) methodType() (TypeRef, bool, error) {
+
+	err := p.expect("(")
+	if err != nil {
+		return nil, false, err
+	}
+
+	stream := false
+	if p.is("stream") && p.lookAhead(1).kind == tokenIdent {
+		p.next()
+		stream = true
+	}
+
+	t, err := p.typeRef()
+	if err != nil {
+		return nil, false, err
+	}
+	return t, stream, p.expect(")")
+}
+
+// method parses a method and its options.
+func (p *parser) method(c Comments) (Method, error) {
+
+	p.next()
+
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	params := MethodParams{}
+	params.RequestType, params.ClientStreaming, err = p.methodType()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expect("returns")
+	if err != nil {
+		return nil, err
+	}
+	params.ResponseType, params.ServerStreaming, err = p.methodType()
+	if err != nil {
+		return nil, err
+	}
+
+	m := NewMethod(name, params)
+
+	if p.is(";") {
+		p.next()
+		p.trailing(&c)
+		return m.SetComments(c), nil
+	}
+
+	err = p.openBlock(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	for !p.is("}") && p.peek().kind != tokenEOF {
+
+		ec := p.leading()
+
+		switch {
+		case p.is("}"):
+		case p.is(";"):
+			p.next()
+		case p.is("option"):
+			var o Option
+			o, err = p.optionStatement(ec)
+			m.AddOptions(o)
+		default:
+			err = p.errorf(*p.peek(), "unexpected %s", p.peek())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.closeBlock("method", name, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.SetComments(c), nil
+}
@@ -0,0 +1,397 @@
+package proto_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	cases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name: "proto3",
+			src: `// Orders API.
+
+// The orders package.
+syntax = "proto3"; // generated
+
+package orders.v1;
+
+import public "common/money.proto";
+
+option go_package = "example.com/orders";
+option (my.opt) = {
+  name: "x";
+};
+
+/* Status of an order. */
+enum Status {
+  option allow_alias = true;
+  reserved 3, 5 to 9, 20 to max;
+  reserved "OLD";
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1 [deprecated = true]; // active
+  STATUS_RUNNING = 1;
+}
+
+message Order {
+  option deprecated = true;
+  reserved 4;
+  // The id.
+  string id = 1 [json_name = "ID", (validate.rules).string.min_len = 1];
+  optional int64 total = 2;
+  repeated common.money.Amount amounts = 3;
+  map<string, Item> items = 5;
+  .google.protobuf.Timestamp created = 8;
+  oneof payload {
+    string text = 6;
+    bytes data = 7;
+  }
+  message Item {
+    double price = 1 [(units.scale) = 1.5, (units.min) = -3];
+    int64 low = 2 [(units.floor) = -9223372036854775808];
+    uint64 high = 3 [(units.ceil) = 18446744073709551615];
+  }
+}
+
+/*
+ * The orders service.
+ * Serves orders.
+ */
+service Orders {
+  option (google.api.default_host) = "orders.example.com";
+  rpc Get (GetRequest) returns (Order) {
+  }
+  rpc Watch (stream GetRequest) returns (stream Order) {
+    option deprecated = true;
+  }
+}
+
+`,
+		},
+		{
+			name: "message values",
+			src: `syntax = "proto3";
+
+package unit;
+
+option (my.opt) = {
+  name: "x";
+  count: -3
+  ratio: 2.5,
+  kind: KIND_A
+  enabled: true
+  limit: -inf
+  ids: [1, 0x2, -3]
+  names: []
+  limits {
+    max: 10
+  }
+  nested: {
+    [my.ext]: "y"
+  }
+  any {
+    [type.googleapis.com/unit.Order] {
+      id: "a"
+    }
+  }
+  rules: [{
+    name: "a"
+  }, {
+  }]
+};
+
+`,
+		},
+		{
+			name: "keyword names",
+			src: `syntax = "proto3";
+
+package unit;
+
+enum enum {
+  option = 0;
+  reserved = 1;
+}
+
+message message {
+  message message = 1;
+  enum enum = 2;
+  oneof oneof = 3;
+  reserved reserved = 4;
+  extensions extensions = 5;
+  oneof choice {
+    message option = 6;
+  }
+}
+
+service service {
+  rpc rpc (message) returns (message) {
+  }
+}
+
+`,
+		},
+		{
+			name: "closing comments",
+			src: `syntax = "proto2";
+
+package unit;
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  // More values to come.
+}
+
+message Order {
+  optional string id = 1; // note
+  optional group Item = 3 {
+    optional string url = 4;
+    // Only urls.
+  }
+  oneof payload {
+    string text = 2;
+    // No other payloads yet.
+  }
+  extensions 100 to 199;
+  // Totals were removed.
+
+  // Keep this comment last.
+}
+
+extend Order {
+  optional int32 rank = 100;
+  // Extension fields end here.
+}
+
+service Orders {
+  rpc Get (Order) returns (Order) {
+    // No options.
+  }
+  // End of the service.
+}
+
+// End of the file.
+
+`,
+		},
+		{
+			name: "statement comments",
+			src: `syntax = "proto3";
+
+// About the package.
+package foo; // pkg
+
+// about import
+import "x.proto"; // trailing import
+import public "y.proto";
+
+message M {
+}
+
+`,
+		},
+		{
+			name: "proto2",
+			src: `syntax = "proto2";
+
+package legacy;
+
+message Search {
+  required string query = 1 [default = "all"];
+  optional group Result = 2 {
+    optional string url = 3;
+  }
+  extensions 100 to 199;
+}
+
+extend Search {
+  optional int32 rank = 100;
+}
+
+`,
+		},
+		{
+			name: "editions",
+			src: `edition = "2023";
+
+package unit;
+
+option features.field_presence = IMPLICIT;
+option features.(pb.cpp).string_type = VIEW;
+option features.(pb.go).legacy_unmarshal_json_enum = true;
+option features.speed = FAST;
+
+message Order {
+  reserved old;
+  string id = 1 [features.field_presence = EXPLICIT, features.(pb.java).utf8_validation = NONE];
+}
+
+`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			f, err := proto.Parse("unit.proto", strings.NewReader(tt.src))
+			r.NoError(err)
+			a.Equal("unit.proto", f.GetPath())
+			buf := &bytes.Buffer{}
+			r.NoError(f.Write(buf))
+			a.Equal(tt.src, buf.String())
+		})
+	}
+}
+
+func TestParse_Stable(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	src := `syntax = 'proto3';
+package unit;
+import "a.proto"; import "a.proto";
+message Outer { message Inner { int32 n = 1; } Inner inner = 1;; int32 octal = 017 [(my.max) = 0x1F]; enum Kind { KIND_UNSPECIFIED = 0x0; } }
+service S { rpc M (.unit.Outer) returns (Outer); }
+option (my.opt) = { limits < max: 1 > };
+`
+
+	write := func(src string) string {
+		f, err := proto.Parse("", strings.NewReader(src))
+		r.NoError(err)
+		buf := &bytes.Buffer{}
+		r.NoError(f.Write(buf))
+		return buf.String()
+	}
+
+	first := write(src)
+	a.Equal(first, write(first))
+	a.Equal(`syntax = "proto3";
+
+package unit;
+
+import "a.proto";
+
+option (my.opt) = {
+  limits {
+    max: 1
+  }
+};
+
+message Outer {
+  Inner inner = 1;
+  int32 octal = 15 [(my.max) = 31];
+  enum Kind {
+    KIND_UNSPECIFIED = 0;
+  }
+
+  message Inner {
+    int32 n = 1;
+  }
+}
+
+service S {
+  rpc M (.unit.Outer) returns (Outer) {
+  }
+}
+
+`, first)
+}
+
+func TestParse_Error(t *testing.T) {
+
+	r := require.New(t)
+
+	cases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "missing semicolon",
+			src:      "syntax = \"proto3\";\n\nmessage Order {\n  string id = 1\n}\n",
+			expected: `orders.proto:5:1: expected ";", found "}"`,
+		},
+		{
+			name:     "unterminated string",
+			src:      "syntax = \"proto3;\n",
+			expected: "orders.proto:1:10: unterminated string",
+		},
+		{
+			name:     "unclosed message",
+			src:      "syntax = \"proto3\";\nmessage Order {\n  string id = 1;\n",
+			expected: "orders.proto:4:1: message Order is not closed",
+		},
+		{
+			name:     "unknown syntax",
+			src:      "syntax = \"proto4\";\n",
+			expected: `orders.proto:1:10: unknown syntax "proto4"`,
+		},
+		{
+			name:     "feature set to a string",
+			src:      "edition = \"2023\";\noption features.field_presence = \"IMPLICIT\";\n",
+			expected: "orders.proto:2:34: feature features.field_presence must be set to an identifier",
+		},
+		{
+			name:     "number out of range",
+			src:      "syntax = \"proto3\";\nmessage Order {\n  string id = 4294967296;\n}\n",
+			expected: "orders.proto:3:15: invalid integer 4294967296",
+		},
+		{
+			name:     "digit separators",
+			src:      "syntax = \"proto3\";\nmessage Order {\n  int32 a = 1_000;\n}\n",
+			expected: "orders.proto:3:13: invalid integer 1_000",
+		},
+		{
+			name:     "binary literal",
+			src:      "syntax = \"proto3\";\nmessage Order {\n  int32 a = 0b11;\n}\n",
+			expected: "orders.proto:3:13: invalid integer 0b11",
+		},
+		{
+			name:     "octal literal with prefix",
+			src:      "syntax = \"proto3\";\noption (my.mode) = 0o17;\n",
+			expected: "orders.proto:2:20: invalid integer 0o17",
+		},
+		{
+			name:     "option value out of range",
+			src:      "syntax = \"proto3\";\noption (my.min) = -9223372036854775809;\n",
+			expected: "orders.proto:2:19: invalid integer -9223372036854775809",
+		},
+		{
+			name:     "unclosed message value",
+			src:      "syntax = \"proto3\";\noption (my.opt) = { name: \"x\";\n",
+			expected: "orders.proto:3:1: message value is not closed",
+		},
+		{
+			name:     "missing message value",
+			src:      "syntax = \"proto3\";\noption (my.opt) = { name: ; };\n",
+			expected: `orders.proto:2:27: expected value, found ";"`,
+		},
+		{
+			name:     "unexpected statement",
+			src:      "syntax = \"proto3\";\nrpc Get;\n",
+			expected: `orders.proto:2:1: unexpected "rpc"`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			_, err := proto.Parse("orders.proto", strings.NewReader(tt.src))
+			var pe *proto.ParseError
+			r.ErrorAs(err, &pe)
+			r.EqualError(err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package proto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Sink receives the files written by a Project. Create returns a writer for the file at the slash-separated path,
+// which is closed once the file has been written.
+type Sink interface {
+	Create(path string) (io.WriteCloser, error)
+}
+
+// dirSink is a Sink which writes files below a directory.
+type dirSink struct {
+	dir string
+}
+
+// Create creates the file at the path below the directory, creating any missing parent directories.
+func (d *dirSink) Create(p string) (io.WriteCloser, error) {
+	name := filepath.Join(d.dir, filepath.FromSlash(p))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(name)
+}
+
+// NewDirSink creates a Sink which writes files below the directory dir.
+func NewDirSink(dir string) Sink {
+	return &dirSink{
+		dir: dir,
+	}
+}
+
+// Project holds many related files keyed by their path. Types declared in its files can be looked up by their
+// fully-qualified name, and external types naming them are imported by the path of the declaring file.
+type Project interface {
+	AddFiles(f ...File) Project
+	GetFile(path string) File
+	ResolveType(fullName string) (TypeRef, error)
+	Write(s Sink) error
+	WriteDir(dir string) error
+}
+
+// project represents a set of files which are written together.
+type project struct {
+	files []*file
+}
+
+// AddFiles adds one or more files to the project and returns the updated Project.
+func (p *project) AddFiles(f ...File) Project {
+	for _, _f := range f {
+		ff := _f.impl()
+		ff.project = p
+		p.files = append(p.files, ff)
+	}
+	return p
+}
+
+// GetFile returns the file with the path, or nil if the project has no such file.
+func (p *project) GetFile(path string) File {
+	for _, f := range p.files {
+		if f.path == path {
+			return f
+		}
+	}
+	return nil
+}
+
+// ResolveType returns the message or enum declared in the project with the fully-qualified name, such as
+// orders.Order.LineItem. A leading dot is ignored.
+func (p *project) ResolveType(fullName string) (TypeRef, error) {
+	if t := p.lookup(fullName); t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown type %s", fullName)
+}
+
+// lookup returns the message or enum with the fully-qualified name, or nil if no file declares it.
+func (p *project) lookup(fullName string) TypeRef {
+	fullName = strings.TrimPrefix(fullName, ".")
+	for _, f := range p.files {
+		if t := lookupType(f.enums, f.messages, fullName); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// lookupType returns the enum or message, including nested declarations, with the fully-qualified name.
+func lookupType(es []Enum, ms []Message, fullName string) TypeRef {
+	for _, e := range es {
+		if qualifiedName(e) == fullName {
+			return e
+		}
+	}
+	for _, m := range ms {
+		if qualifiedName(m) == fullName {
+			return m
+		}
+		if nm, ok := m.(*message); ok {
+			if t := lookupType(nm.enums, nm.messages, fullName); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// qualifiedName returns the name of the type qualified with its package.
+func qualifiedName(t TypeRef) string {
+	if t.GetPackageName() == "" {
+		return t.GetTypeName()
+	}
+	return t.GetPackageName() + "." + t.GetTypeName()
+}
+
+// checkPaths returns an error if a file has no path, a path which is not relative and clean, or the same path as
+// another file.
+func (p *project) checkPaths() error {
+	paths := map[string]bool{}
+	for _, f := range p.files {
+		switch {
+		case f.path == "":
+			return fmt.Errorf("file of package %s has no path", f.packageName)
+		case path.IsAbs(f.path) || path.Clean(f.path) != f.path || strings.HasPrefix(f.path, "../"):
+			return fmt.Errorf("file %s: paths must be relative and clean", f.path)
+		case paths[f.path]:
+			return fmt.Errorf("file %s: path is used more than once", f.path)
+		}
+		paths[f.path] = true
+	}
+	return nil
+}
+
+// checkCycles returns an error naming the files of the first import cycle between files of the project, following
+// the imports the files have when written with their contexts.
+func (p *project) checkCycles(contexts map[*file]*fileContext) error {
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	var stack []string
+
+	var visit func(f *file) error
+	visit = func(f *file) error {
+		state[f.path] = visiting
+		stack = append(stack, f.path)
+		for _, i := range contexts[f].allImports() {
+			next, ok := p.GetFile(i.GetPath()).(*file)
+			if !ok {
+				continue
+			}
+			switch state[next.path] {
+			case visiting:
+				for n, s := range stack {
+					if s == next.path {
+						return fmt.Errorf("import cycle: %s", strings.Join(append(stack[n:], next.path), " -> "))
+					}
+				}
+			case 0:
+				if err := visit(next); err != nil {
+					return err
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[f.path] = visited
+		return nil
+	}
+
+	for _, f := range p.files {
+		if state[f.path] == 0 {
+			if err := visit(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Write checks the paths of the files and the imports between them, then writes each file to the sink. Every file
+// is validated and rendered before the first file is created, so nothing is written if a file is invalid or the
+// files import each other in a cycle.
+func (p *project) Write(s Sink) error {
+
+	if err := p.checkPaths(); err != nil {
+		return err
+	}
+
+	contexts := map[*file]*fileContext{}
+	for _, f := range p.files {
+		ctx, err := f.prepare()
+		if err != nil {
+			return fmt.Errorf("file %s: %w", f.path, err)
+		}
+		contexts[f] = ctx
+	}
+
+	if err := p.checkCycles(contexts); err != nil {
+		return err
+	}
+
+	bufs := make([]*bytes.Buffer, len(p.files))
+	for n, f := range p.files {
+		bufs[n] = &bytes.Buffer{}
+		if err := f.render(bufs[n], contexts[f]); err != nil {
+			return fmt.Errorf("file %s: %w", f.path, err)
+		}
+	}
+
+	for n, f := range p.files {
+		if err := writeFile(s, f.path, bufs[n]); err != nil {
+			return fmt.Errorf("file %s: %w", f.path, err)
+		}
+	}
+
+	return nil
+}
+
+// writeFile writes the rendered contents of a file to the sink.
+func writeFile(s Sink, path string, buf *bytes.Buffer) error {
+	w, err := s.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return errors.Join(err, w.Close())
+}
+
+// WriteDir writes the files of the project below the directory dir, using their paths relative to it.
+func (p *project) WriteDir(dir string) error {
+	return p.Write(NewDirSink(dir))
+}
+
+// NewProject creates a new Project holding the files.
+func NewProject(files ...File) Project {
+	p := &project{}
+	p.AddFiles(files...)
+	return p
+}
@@ -0,0 +1,220 @@
+package proto_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/activatedio/protogen/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memSink collects written files in memory.
+type memSink map[string]*bytes.Buffer
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func (m memSink) Create(path string) (io.WriteCloser, error) {
+	buf := &bytes.Buffer{}
+	m[path] = buf
+	return nopCloser{buf}, nil
+}
+
+func TestProject_Write(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	cases := []struct {
+		name    string
+		arrange func() proto.Project
+		assert  func(got memSink, err error)
+	}{
+		{
+			name: "cross file references",
+			arrange: func() proto.Project {
+				money := proto.NewFile("common.money", proto.FileParams{Path: "common/money.proto"}).AddMessages(
+					proto.NewMessage("Amount").AddFields(
+						proto.NewField("units", proto.FieldParams{FieldType: proto.Int64, Number: 1}),
+					),
+				)
+				orders := proto.NewFile("orders", proto.FileParams{Path: "orders/orders.proto"}).AddMessages(
+					proto.NewMessage("Order").AddFields(
+						proto.NewField("total", proto.FieldParams{FieldType: proto.NewExternalType("common.money.Amount"), Number: 1}),
+					),
+				)
+				return proto.NewProject(money, orders)
+			},
+			assert: func(got memSink, err error) {
+				r.NoError(err)
+				a.Len(got, 2)
+				a.Equal(`syntax = "proto3";
+
+package orders;
+
+import "common/money.proto";
+
+message Order {
+  .common.money.Amount total = 1;
+}
+
+`, got["orders/orders.proto"].String())
+			},
+		},
+		{
+			name: "import cycle",
+			arrange: func() proto.Project {
+				a := proto.NewFile("a", proto.FileParams{Path: "a.proto"}).AddImports(proto.NewImport("b.proto"))
+				b := proto.NewFile("b", proto.FileParams{Path: "b.proto"}).AddImports(proto.NewImport("c.proto"))
+				c := proto.NewFile("c", proto.FileParams{Path: "c.proto"}).AddImports(proto.NewImport("b.proto"))
+				return proto.NewProject(a, b, c)
+			},
+			assert: func(got memSink, err error) {
+				r.EqualError(err, "import cycle: b.proto -> c.proto -> b.proto")
+				a.Empty(got)
+			},
+		},
+		{
+			name: "import cycle through type references",
+			arrange: func() proto.Project {
+				a := proto.NewFile("a", proto.FileParams{Path: "a.proto"}).AddMessages(
+					proto.NewMessage("A").AddFields(
+						proto.NewField("b", proto.FieldParams{FieldType: proto.NewExternalType("b.B"), Number: 1}),
+					),
+				)
+				b := proto.NewFile("b", proto.FileParams{Path: "b.proto"}).AddMessages(
+					proto.NewMessage("B").AddFields(
+						proto.NewField("a", proto.FieldParams{FieldType: proto.NewExternalType("a.A"), Number: 1}),
+					),
+				)
+				return proto.NewProject(a, b)
+			},
+			assert: func(got memSink, err error) {
+				r.EqualError(err, "import cycle: a.proto -> b.proto -> a.proto")
+				a.Empty(got)
+			},
+		},
+		{
+			name: "invalid file after a valid one",
+			arrange: func() proto.Project {
+				valid := proto.NewFile("a", proto.FileParams{Path: "a.proto"})
+				invalid := proto.NewFile("b", proto.FileParams{Path: "b.proto"}).AddMessages(
+					proto.NewMessage("B").AddFields(
+						proto.NewField("x", proto.FieldParams{FieldType: proto.Int32, Number: 1}),
+						proto.NewField("y", proto.FieldParams{FieldType: proto.Int32, Number: 1}),
+					),
+				)
+				return proto.NewProject(valid, invalid)
+			},
+			assert: func(got memSink, err error) {
+				r.EqualError(err, "file b.proto: message B: field y reuses number 1 of field x")
+				a.Empty(got)
+			},
+		},
+		{
+			name: "file failing validation after a valid one",
+			arrange: func() proto.Project {
+				valid := proto.NewFile("a", proto.FileParams{Path: "a.proto"})
+				invalid := proto.NewFile("b", proto.FileParams{Path: "b.proto", Validate: true}).AddMessages(
+					proto.NewMessage("B").AddFields(
+						proto.NewField("x", proto.FieldParams{FieldType: proto.Int32, Number: 0}),
+					),
+				)
+				return proto.NewProject(valid, invalid)
+			},
+			assert: func(got memSink, err error) {
+				r.EqualError(err, "file b.proto: message B > field x: number 0 is outside the range 1 to 536870911")
+				a.Empty(got)
+			},
+		},
+		{
+			name: "duplicate path",
+			arrange: func() proto.Project {
+				return proto.NewProject(
+					proto.NewFile("a", proto.FileParams{Path: "a.proto"}),
+					proto.NewFile("b", proto.FileParams{Path: "a.proto"}),
+				)
+			},
+			assert: func(_ memSink, err error) {
+				r.EqualError(err, "file a.proto: path is used more than once")
+			},
+		},
+		{
+			name: "path not relative",
+			arrange: func() proto.Project {
+				return proto.NewProject(proto.NewFile("a", proto.FileParams{Path: "../a.proto"}))
+			},
+			assert: func(_ memSink, err error) {
+				r.EqualError(err, "file ../a.proto: paths must be relative and clean")
+			},
+		},
+		{
+			name: "missing path",
+			arrange: func() proto.Project {
+				return proto.NewProject(proto.NewFile("a"))
+			},
+			assert: func(_ memSink, err error) {
+				r.EqualError(err, "file of package a has no path")
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(_ *testing.T) {
+			got := memSink{}
+			err := tt.arrange().Write(got)
+			tt.assert(got, err)
+		})
+	}
+}
+
+func TestProject_ResolveType(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	item := proto.NewMessage("LineItem")
+	status := proto.NewEnum("Status")
+	p := proto.NewProject(
+		proto.NewFile("orders.v1", proto.FileParams{Path: "orders.proto"}).AddEnums(status).AddMessages(
+			proto.NewMessage("Order").AddMessages(item),
+		),
+	)
+
+	got, err := p.ResolveType(".orders.v1.Order.LineItem")
+	r.NoError(err)
+	a.Same(item, got)
+
+	got, err = p.ResolveType("orders.v1.Status")
+	r.NoError(err)
+	a.Same(status, got)
+
+	_, err = p.ResolveType("orders.v1.Missing")
+	r.EqualError(err, "unknown type orders.v1.Missing")
+
+	a.NotNil(p.GetFile("orders.proto"))
+	a.Nil(p.GetFile("missing.proto"))
+}
+
+func TestProject_WriteDir(t *testing.T) {
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	dir := t.TempDir()
+	err := proto.NewProject(proto.NewFile("unit", proto.FileParams{Path: "unit/v1/unit.proto"})).WriteDir(dir)
+	r.NoError(err)
+
+	got, err := os.ReadFile(filepath.Join(dir, "unit", "v1", "unit.proto"))
+	r.NoError(err)
+	a.Equal("syntax = \"proto3\";\n\npackage unit;\n\n", string(got))
+}
@@ -0,0 +1,148 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/activatedio/protogen"
+)
+
+// Reserved represents a reserved statement which marks field numbers or names as unavailable for use.
+type Reserved interface {
+	protogen.Renderer
+	ReservesNumber(int32) bool
+	ReservesName(string) bool
+}
+
+// numberRange is an inclusive range of numbers. A range with toMax set extends to the maximum number.
+type numberRange struct {
+	start int32
+	end   int32
+	toMax bool
+}
+
+// includes reports whether the number falls within the range.
+func (r numberRange) includes(n int32) bool {
+	return n >= r.start && (r.toMax || n <= r.end)
+}
+
+// check returns an error if the range ends before it starts.
+func (r numberRange) check() error {
+	if !r.toMax && r.end < r.start {
+		return fmt.Errorf("range %s ends before it starts", r)
+	}
+	return nil
+}
+
+// String returns the range as written in reserved and extensions statements.
+func (r numberRange) String() string {
+	if r.toMax {
+		return fmt.Sprintf("%d to max", r.start)
+	}
+	return fmt.Sprintf("%d to %d", r.start, r.end)
+}
+
+// reserved holds either a set of reserved numbers and ranges or a set of reserved names.
+type reserved struct {
+	numbers []int32
+	ranges  []numberRange
+	names   []string
+}
+
+// ReservesNumber reports whether the number is one of the reserved numbers or falls within a reserved range.
+func (r *reserved) ReservesNumber(n int32) bool {
+	for _, rn := range r.numbers {
+		if rn == n {
+			return true
+		}
+	}
+	for _, rg := range r.ranges {
+		if rg.includes(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReservesName reports whether the name is one of the reserved names.
+func (r *reserved) ReservesName(name string) bool {
+	for _, rn := range r.names {
+		if rn == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validate reports every reserved range which ends before it starts.
+func (r *reserved) validate(v *validator) {
+	for _, rg := range r.ranges {
+		if err := rg.check(); err != nil {
+			v.report(fmt.Errorf("reserved %w", err))
+		}
+	}
+}
+
+// Render writes the reserved statement to the provided Output. Names are written as string literals,
+// or as identifiers when written into an editions file.
+func (r *reserved) Render(o protogen.Output) error {
+
+	var parts []string
+
+	for _, n := range r.numbers {
+		parts = append(parts, fmt.Sprintf("%d", n))
+	}
+	for _, rg := range r.ranges {
+		parts = append(parts, rg.String())
+	}
+	for _, n := range r.names {
+		if syntaxOf(o) == SyntaxEditions {
+			parts = append(parts, n)
+		} else {
+			parts = append(parts, fmt.Sprintf(`"%s"`, n))
+		}
+	}
+
+	return o.WriteLines(fmt.Sprintf("reserved %s;", strings.Join(parts, ", ")))
+}
+
+// NewReservedNumbers creates a Reserved statement for the specified numbers.
+func NewReservedNumbers(numbers ...int32) Reserved {
+	return &reserved{
+		numbers: numbers,
+	}
+}
+
+// NewReservedRange creates a Reserved statement for the inclusive range of numbers from start to end.
+func NewReservedRange(start, end int32) Reserved {
+	return &reserved{
+		ranges: []numberRange{{start: start, end: end}},
+	}
+}
+
+// NewReservedRangeToMax creates a Reserved statement for all numbers from start up to the maximum allowed number.
+func NewReservedRangeToMax(start int32) Reserved {
+	return &reserved{
+		ranges: []numberRange{{start: start, toMax: true}},
+	}
+}
+
+// NewReservedNames creates a Reserved statement for the specified names.
+func NewReservedNames(names ...string) Reserved {
+	return &reserved{
+		names: names,
+	}
+}
+
+// checkReserved returns an error if the name or number of a field or enum value is reserved.
+func checkReserved(rs []Reserved, kind, name string, number int32) error {
+	for _, r := range rs {
+		if r.ReservesName(name) {
+			return fmt.Errorf("%s %s uses a reserved name", kind, name)
+		}
+		if r.ReservesNumber(number) {
+			return fmt.Errorf("%s %s uses reserved number %d", kind, name, number)
+		}
+	}
+	return nil
+}
@@ -9,13 +9,30 @@ import (
 // Service represents an interface that extends Renderer and allows adding RPC methods.
 type Service interface {
 	protogen.Renderer
+	SetComments(c Comments) Service
+	AddOptions(o ...Option) Service
 	AddMethods(m ...Method) Service
 }
 
 // service is a struct that represents an RPC service with a name and a collection of methods.
 type service struct {
-	name    string
-	methods []Method
+	name     string
+	comments Comments
+	options  []Option
+	methods  []Method
+}
+
+// SetComments sets the comments written with the service and returns the updated Service instance.
+func (s *service) SetComments(c Comments) Service {
+	s.comments = c
+	return s
+}
+
+// AddOptions appends one or more Option instances to the service and returns the updated Service instance.
+// Options are rendered at the top of the service body.
+func (s *service) AddOptions(o ...Option) Service {
+	s.options = append(s.options, o...)
+	return s
 }
 
 // AddMethods appends one or more Method instances to the service and returns the updated Service instance.
@@ -24,25 +41,64 @@ func (s *service) AddMethods(m ...Method) Service {
 	return s
 }
 
+// applyFile returns an error if the options of the service or its methods are not valid for the syntax.
+func (s *service) applyFile(ctx *fileContext) error {
+	err := checkFeatures(ctx.syntax, targetService, s.options)
+	if err == nil {
+		err = applyElements(ctx, s.methods)
+	}
+	if err != nil {
+		return fmt.Errorf("service %s: %w", s.name, err)
+	}
+	return nil
+}
+
+// validate reports every problem with the service and its methods, including methods which share a name.
+func (s *service) validate(v *validator) {
+	v.within("service", s.name, func() {
+		v.report(checkIdent(s.name))
+		v.report(checkFeatures(v.syntax, targetService, s.options))
+		names := map[string]bool{}
+		for _, m := range s.methods {
+			if mt, ok := m.(*method); ok {
+				if names[mt.name] {
+					v.report(fmt.Errorf("method name %s is used more than once", mt.name))
+				}
+				names[mt.name] = true
+			}
+		}
+		validateElements(v, s.methods)
+	})
+}
+
 // Render generates a structured representation of the service and writes it to the given Output, returning any encountered error.
 func (s *service) Render(o protogen.Output) error {
 
-	var err error
+	err := writeLeadingComments(o, s.comments)
+	if err != nil {
+		return err
+	}
 
-	err = o.WriteLines(fmt.Sprintf("service %s {", s.name))
+	err = o.WriteLines(fmt.Sprintf("service %s {%s", s.name, trailingComment(s.comments)))
 
 	if err != nil {
 		return err
 	}
 
-	for _, m := range s.methods {
-		i := protogen.NewIndentingOutput(o, 2)
-		err = m.Render(i)
+	i := indent(o)
+
+	for _, r := range [][]protogen.Renderer{toRenderers(s.options), toRenderers(s.methods)} {
+		err = renderElements(i, r)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = writeClosingComments(i, s.comments)
+	if err != nil {
+		return err
+	}
+
 	return o.WriteLines("}", "")
 
 }
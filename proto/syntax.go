@@ -0,0 +1,50 @@
+package proto
+
+import "fmt"
+
+// Syntax selects the language version a file is written in.
+type Syntax int
+
+// SyntaxProto3 writes files using syntax = "proto3", which is the default.
+// SyntaxProto2 writes files using syntax = "proto2".
+// SyntaxEditions writes files using an edition statement, such as edition = "2023".
+const (
+	SyntaxProto3 Syntax = iota
+	SyntaxProto2
+	SyntaxEditions
+)
+
+// String returns the name of the syntax as written in the syntax statement, or editions for SyntaxEditions.
+func (s Syntax) String() string {
+	switch s {
+	case SyntaxProto2:
+		return "proto2"
+	case SyntaxEditions:
+		return "editions"
+	default:
+		return "proto3"
+	}
+}
+
+// Edition identifies the edition of a file written with SyntaxEditions.
+type Edition string
+
+// Edition2023 is the first protobuf edition, and the default for files written with SyntaxEditions.
+const Edition2023 Edition = "2023"
+
+// checkLabelsRequired returns an error if a proto2 field outside a oneof has no label. Map fields are exempt.
+func checkLabelsRequired(s Syntax, fs []Field) error {
+	if s != SyntaxProto2 {
+		return nil
+	}
+	for _, f := range fs {
+		if f.GetLabel() != LabelImplicit {
+			continue
+		}
+		if m, ok := f.(*field); ok && m.keyType != "" {
+			continue
+		}
+		return fmt.Errorf("field %s: proto2 fields require a label", f.GetName())
+	}
+	return nil
+}
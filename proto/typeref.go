@@ -0,0 +1,164 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TypeRef refers to a type used by a field or by the request or response of a method. It is implemented by
+// ScalarType, by Message and Enum declarations, and by external types created with NewExternalType.
+type TypeRef interface {
+	// GetTypeName returns the name of the type within its package, such as int64 or Order.LineItem
+	GetTypeName() string
+	// GetPackageName returns the package the type is declared in, which is empty for scalar types
+	GetPackageName() string
+}
+
+// ScalarType is one of the scalar value types of the protobuf language.
+type ScalarType string
+
+// Scalar value types.
+const (
+	Double   ScalarType = "double"
+	Float    ScalarType = "float"
+	Int32    ScalarType = "int32"
+	Int64    ScalarType = "int64"
+	Uint32   ScalarType = "uint32"
+	Uint64   ScalarType = "uint64"
+	Sint32   ScalarType = "sint32"
+	Sint64   ScalarType = "sint64"
+	Fixed32  ScalarType = "fixed32"
+	Fixed64  ScalarType = "fixed64"
+	Sfixed32 ScalarType = "sfixed32"
+	Sfixed64 ScalarType = "sfixed64"
+	Bool     ScalarType = "bool"
+	String   ScalarType = "string"
+	Bytes    ScalarType = "bytes"
+)
+
+// scalarTypes holds all known scalar types.
+var scalarTypes = map[ScalarType]bool{
+	Double: true, Float: true, Int32: true, Int64: true, Uint32: true, Uint64: true, Sint32: true, Sint64: true,
+	Fixed32: true, Fixed64: true, Sfixed32: true, Sfixed64: true, Bool: true, String: true, Bytes: true,
+}
+
+// Valid reports whether the scalar type is one of the scalar types of the protobuf language.
+func (s ScalarType) Valid() bool {
+	return scalarTypes[s]
+}
+
+// GetTypeName returns the keyword of the scalar type.
+func (s ScalarType) GetTypeName() string {
+	return string(s)
+}
+
+// GetPackageName returns an empty string, since scalar types do not belong to a package.
+func (s ScalarType) GetPackageName() string {
+	return ""
+}
+
+// externalType refers to a message or enum by its fully-qualified name, for types which are not built as
+// Message or Enum values, such as google.protobuf.Timestamp.
+// A type named in parsed source keeps the name as written, since relative names can only be resolved against the
+// scopes of the source file.
+type externalType struct {
+	fullName    string
+	packageName string
+	typeName    string
+	importPath  string
+	written     string
+}
+
+// GetTypeName returns the name of the type within its package.
+func (e *externalType) GetTypeName() string {
+	return e.typeName
+}
+
+// GetPackageName returns the package of the type.
+func (e *externalType) GetPackageName() string {
+	return e.packageName
+}
+
+// NewExternalType creates a TypeRef for the message or enum with the specified fully-qualified name. The package is
+// taken to end before the first name component starting with a capital letter, or before the last component if
+// none does, following the protobuf style guide. The optional importPath names the file declaring the type, which
+// is imported automatically by files using it.
+func NewExternalType(fullName string, importPath ...string) TypeRef {
+
+	parts := strings.Split(strings.TrimPrefix(fullName, "."), ".")
+	split := len(parts) - 1
+
+	for i, p := range parts {
+		if p != "" && unicode.IsUpper([]rune(p)[0]) {
+			split = i
+			break
+		}
+	}
+
+	e := &externalType{
+		fullName:    fullName,
+		packageName: strings.Join(parts[:split], "."),
+		typeName:    strings.Join(parts[split:], "."),
+	}
+	for _, p := range importPath {
+		e.importPath = p
+	}
+	return e
+}
+
+// newSourceType creates a TypeRef for a type named in parsed source, which is written back exactly as named.
+func newSourceType(name string) TypeRef {
+	e := NewExternalType(name).(*externalType)
+	e.written = name
+	return e
+}
+
+// fullIdentPattern matches a fully-qualified name, optionally with a leading dot.
+var fullIdentPattern = regexp.MustCompile(`^\.?[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// checkTypeRef returns an error if the reference is missing, an unknown scalar type or an invalid name.
+func checkTypeRef(t TypeRef) error {
+	switch r := t.(type) {
+	case nil:
+		return errors.New("missing type")
+	case ScalarType:
+		if !scalarTypes[r] {
+			return fmt.Errorf("unknown scalar type %s", r)
+		}
+	case *externalType:
+		if !fullIdentPattern.MatchString(r.fullName) {
+			return fmt.Errorf("invalid type name %q", r.fullName)
+		}
+	}
+	return nil
+}
+
+// checkMessageRef returns an error if the reference is not valid or refers to a scalar or enum type, for uses where
+// only messages are allowed.
+func checkMessageRef(t TypeRef) error {
+	err := checkTypeRef(t)
+	if err != nil {
+		return err
+	}
+	switch t.(type) {
+	case ScalarType, Enum:
+		return fmt.Errorf("%s is not a message type", typeRefName(t))
+	}
+	return nil
+}
+
+// typeRefName returns the name used to refer to the type. Scalar types are written as keywords, declared messages and
+// enums by their name within their package, and external types by their fully-qualified name.
+func typeRefName(t TypeRef) string {
+	switch r := t.(type) {
+	case nil:
+		return ""
+	case *externalType:
+		return r.fullName
+	default:
+		return t.GetTypeName()
+	}
+}
@@ -1,6 +1,10 @@
 package proto
 
-import "github.com/activatedio/protogen"
+import (
+	"bytes"
+
+	"github.com/activatedio/protogen"
+)
 
 func toRenderers[T protogen.Renderer](ts []T) []protogen.Renderer {
 	renderers := make([]protogen.Renderer, len(ts))
@@ -9,3 +13,87 @@ func toRenderers[T protogen.Renderer](ts []T) []protogen.Renderer {
 	}
 	return renderers
 }
+
+func toAny[T any](ts []T) []any {
+	res := make([]any, len(ts))
+	for i, t := range ts {
+		res[i] = t
+	}
+	return res
+}
+
+// renderToString renders a single element and returns the written text.
+func renderToString(r protogen.Renderer) (string, error) {
+	buf := &bytes.Buffer{}
+	err := r.Render(protogen.NewWriterOutput(buf))
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// blockOutput indents the body of a block declaration and holds back blank lines until more content follows.
+// Nested declarations end with a blank line, which would otherwise appear right before the closing brace.
+type blockOutput struct {
+	delegate protogen.Output
+	blank    bool
+}
+
+func (b *blockOutput) flush() error {
+	if !b.blank {
+		return nil
+	}
+	b.blank = false
+	return b.delegate.WriteLines("")
+}
+
+func (b *blockOutput) StartLine() error {
+	err := b.flush()
+	if err != nil {
+		return err
+	}
+	return b.delegate.StartLine()
+}
+
+func (b *blockOutput) Write(s string) error {
+	return b.delegate.Write(s)
+}
+
+// WriteLines writes the provided lines to the indented delegate, deferring blank lines.
+func (b *blockOutput) WriteLines(s ...string) error {
+	for _, l := range s {
+		if l == "" {
+			b.blank = true
+			continue
+		}
+		err := b.flush()
+		if err != nil {
+			return err
+		}
+		err = b.delegate.WriteLines(l)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newBlockOutput creates an Output for the body of a block declaration written to o.
+func newBlockOutput(o protogen.Output) protogen.Output {
+	return withContext(&blockOutput{
+		delegate: protogen.NewIndentingOutput(o, 2),
+	}, o)
+}
+
+// nestable is implemented by declarations which can be nested inside a message.
+type nestable interface {
+	setParent(Message)
+}
+
+// typeName returns the dotted name of a declaration relative to its package, given its enclosing message.
+func typeName(parent Message, name string) string {
+	if parent == nil {
+		return name
+	}
+	return parent.GetTypeName() + "." + name
+}
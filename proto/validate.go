@@ -0,0 +1,206 @@
+package proto
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxFieldNumber is the largest field number allowed, 2^29-1.
+const maxFieldNumber = 1<<29 - 1
+
+// Field numbers from firstImplementationNumber to lastImplementationNumber are reserved for the protobuf
+// implementation.
+const (
+	firstImplementationNumber = 19000
+	lastImplementationNumber  = 19999
+)
+
+// Violation is a single problem found when validating a file. Path locates the element the problem concerns,
+// such as message Order > field id.
+type Violation struct {
+	Path    []string
+	Message string
+}
+
+// Error returns the path and message of the violation.
+func (v Violation) Error() string {
+	if len(v.Path) == 0 {
+		return v.Message
+	}
+	return strings.Join(v.Path, " > ") + ": " + v.Message
+}
+
+// ValidationError is returned by File.Validate and holds every violation found in the file.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns the violations, one per line.
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, v.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validator walks the elements of a file, tracking the path of the current element and collecting violations.
+type validator struct {
+	syntax     Syntax
+	path       []string
+	violations []Violation
+}
+
+// within runs fn with the element identified by kind and name appended to the current path.
+func (v *validator) within(kind, name string, fn func()) {
+	if name != "" {
+		kind = kind + " " + name
+	}
+	v.path = append(v.path, kind)
+	fn()
+	v.path = v.path[:len(v.path)-1]
+}
+
+// report records a violation of the current element when err is not nil.
+func (v *validator) report(err error) {
+	if err == nil {
+		return
+	}
+	v.violations = append(v.violations, Violation{
+		Path:    append([]string{}, v.path...),
+		Message: err.Error(),
+	})
+}
+
+// reportAll records a violation of the current element for each error.
+func (v *validator) reportAll(errs []error) {
+	for _, err := range errs {
+		v.report(err)
+	}
+}
+
+// err returns a ValidationError holding the violations found, or nil if there are none.
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+// validatable is implemented by elements which check themselves and their children during validation.
+type validatable interface {
+	validate(v *validator)
+}
+
+// validateElements validates each element which implements validatable.
+func validateElements[T any](v *validator, ts []T) {
+	for _, t := range ts {
+		if c, ok := any(t).(validatable); ok {
+			c.validate(v)
+		}
+	}
+}
+
+// identPattern matches an identifier.
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// packageNamePattern matches a package name, a dotted identifier without a leading dot.
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// checkIdent returns an error if the name is empty or is not a valid identifier.
+func checkIdent(name string) error {
+	switch {
+	case name == "":
+		return errors.New("name is empty")
+	case !identPattern.MatchString(name):
+		return fmt.Errorf("invalid identifier %q", name)
+	default:
+		return nil
+	}
+}
+
+// checkFieldNumber returns an error if the number is outside the allowed range or is reserved for the
+// protobuf implementation.
+func checkFieldNumber(n int32) error {
+	switch {
+	case n < 1 || n > maxFieldNumber:
+		return fmt.Errorf("number %d is outside the range 1 to %d", n, maxFieldNumber)
+	case n >= firstImplementationNumber && n <= lastImplementationNumber:
+		return fmt.Errorf("number %d is reserved for the protobuf implementation", n)
+	default:
+		return nil
+	}
+}
+
+// fieldErrors returns an error for each field which shares a name or number with an earlier field, uses a reserved
+// name or number, or uses a number within an extension range.
+func fieldErrors(fs []Field, rs []Reserved, es []ExtensionRange) []error {
+
+	var errs []error
+
+	names := map[string]bool{}
+	numbers := map[int32]string{}
+
+	for _, f := range fs {
+		if names[f.GetName()] {
+			errs = append(errs, fmt.Errorf("field name %s is used more than once", f.GetName()))
+		}
+		names[f.GetName()] = true
+		if other, ok := numbers[f.GetNumber()]; ok {
+			errs = append(errs, fmt.Errorf("field %s reuses number %d of field %s", f.GetName(), f.GetNumber(), other))
+		} else {
+			numbers[f.GetNumber()] = f.GetName()
+		}
+		if err := checkReserved(rs, "field", f.GetName(), f.GetNumber()); err != nil {
+			errs = append(errs, err)
+		}
+		for _, e := range es {
+			if e.IncludesNumber(f.GetNumber()) {
+				errs = append(errs, fmt.Errorf("field %s uses number %d within an extension range", f.GetName(), f.GetNumber()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// scope detects declarations which share a name within a file or message. Enum values are declared in the scope
+// enclosing their enum.
+type scope map[string]string
+
+// declare records a declaration in the scope, returning an error if the name is already declared.
+func (s scope) declare(kind, name string) error {
+	if other, ok := s[name]; ok {
+		return fmt.Errorf("%s %s conflicts with %s %s", kind, name, other, name)
+	}
+	s[name] = kind
+	return nil
+}
+
+// declareTypes records the enums, their values and the messages declared in a scope.
+func (s scope) declareTypes(v *validator, es []Enum, ms []Message) {
+	for _, e := range es {
+		v.report(s.declare("enum", e.GetName()))
+		for _, ev := range e.GetValues() {
+			v.report(s.declare("enum value", ev.GetName()))
+		}
+	}
+	for _, m := range ms {
+		v.report(s.declare("message", m.GetName()))
+	}
+}
+
+// declareMembers records the fields and oneofs of a message in the scope of its nested declarations. Fields sharing
+// a name with each other are reported by fieldErrors, so each field name is only declared once.
+func (s scope) declareMembers(v *validator, fs []Field, os []Oneof) {
+	for _, f := range fs {
+		if s[f.GetName()] != "field" {
+			v.report(s.declare("field", f.GetName()))
+		}
+	}
+	for _, o := range os {
+		v.report(s.declare("oneof", o.GetName()))
+	}
+}
@@ -50,7 +50,10 @@ func (i *indentingOutput) WriteLines(s ...string) error {
 	for _, l := range s {
 
 		sb := strings.Builder{}
-		sb.WriteString(strings.Repeat(" ", i.level))
+		// Blank lines are left unindented so that no trailing whitespace is written
+		if l != "" {
+			sb.WriteString(strings.Repeat(" ", i.level))
+		}
 		sb.WriteString(l)
 
 		err = i.delegate.WriteLines(sb.String())
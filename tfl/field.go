@@ -1,18 +1,9 @@
 package tfl
 
 import (
-	"errors"
-	"fmt"
-	"strings"
-
 	"github.com/activatedio/protogen"
 )
 
-// fieldString is a constant used to represent a string field type in the context of field rendering operations.
-const (
-	fieldString = iota
-)
-
 // Field is an interface representing a renderable field with customizable end delimiters like semicolons or commas.
 // Field extends protogen.Renderer, allowing rendering of structured output using the Render method.
 // EndSemicolon returns the Field with a semicolon appended at the end.
@@ -23,13 +14,13 @@ type Field interface {
 	EndComma() Field
 }
 
-// field represents a structure for defining and rendering fields with specific types, values, and formatting rules.
+// field represents a structure for defining and rendering fields with specific values and formatting rules.
 type field struct {
-	fieldType    int
-	stringValue  string
+	name         string
+	value        Value
+	colon        bool
 	endSemicolon bool
 	endComma     bool
-	name         string
 }
 
 // EndSemicolon sets the field's end marker to a semicolon and returns the updated Field instance.
@@ -44,8 +35,8 @@ func (f *field) EndComma() Field {
 	return f
 }
 
-// Render writes the field's name and value to the provided Output, formatted based on its type and end character settings.
-// Returns an error if writing fails or the field type is unsupported.
+// Render writes the field's name and value to the provided Output, followed by its end character.
+// Returns an error if writing fails.
 func (f *field) Render(o protogen.Output) error {
 
 	err := o.StartLine()
@@ -53,38 +44,51 @@ func (f *field) Render(o protogen.Output) error {
 		return err
 	}
 
-	err = o.Write(fmt.Sprintf("%s: ", f.name))
+	sep := " "
+	if f.colon {
+		sep = ": "
+	}
+
+	err = o.Write(f.name + sep)
 	if err != nil {
 		return err
 	}
 
-	sb := strings.Builder{}
-
-	switch f.fieldType {
-	case fieldString:
-		sb.WriteString(fmt.Sprintf(`"%s"`, f.stringValue))
-	default:
-		return errors.New("unsupported field type")
+	err = f.value.Render(o)
+	if err != nil {
+		return err
 	}
 
+	end := "\n"
 	switch {
 	case f.endSemicolon:
-		sb.WriteString(";")
+		end = ";" + end
 	case f.endComma:
-		sb.WriteString(",")
+		end = "," + end
 	}
 
-	sb.WriteString("\n")
-
-	return o.Write(sb.String())
-
+	return o.Write(end)
 }
 
 // NewStringField creates a new field of type string with the specified name and value.
 func NewStringField(name, value string) Field {
+	return NewField(name, NewStringValue(value))
+}
+
+// NewField creates a new field with the specified name and value, written with a colon after the name. The name may
+// be an extension or Any type URL in square brackets, such as [foo.ext].
+func NewField(name string, value Value) Field {
+	return &field{
+		name:  name,
+		value: value,
+		colon: true,
+	}
+}
+
+// NewMessageField creates a new field holding a nested message, written without a colon as name { ... }.
+func NewMessageField(name string, value MessageValue) Field {
 	return &field{
-		fieldType:   fieldString,
-		name:        name,
-		stringValue: value,
+		name:  name,
+		value: value,
 	}
 }
@@ -48,6 +48,42 @@ func TestMessageValue_Render(t *testing.T) {
   field2: "value2"
   field3: "value3";
   field4: "value4",
+}`, string(got))
+			},
+		},
+		{
+			name: "values",
+			arrange: func() tfl.MessageValue {
+				return tfl.NewMessageValue().AddFields(
+					tfl.NewField("count", tfl.NewScalarValue("-3")),
+					tfl.NewField("status", tfl.NewScalarValue("STATUS_ACTIVE")).EndComma(),
+					tfl.NewField("[ext.flag]", tfl.NewScalarValue("true")),
+					tfl.NewField("ids", tfl.NewListValue(tfl.NewScalarValue("1"), tfl.NewScalarValue("2"))),
+					tfl.NewField("names", tfl.NewListValue()),
+					tfl.NewMessageField("limits", tfl.NewMessageValue().AddFields(
+						tfl.NewField("max", tfl.NewScalarValue("2.5")),
+					)),
+					tfl.NewField("rules", tfl.NewListValue(
+						tfl.NewMessageValue().AddFields(tfl.NewStringField("name", "a")),
+						tfl.NewMessageValue(),
+					)).EndSemicolon(),
+				)
+			},
+			assert: func(got []byte, err error) {
+				r.NoError(err)
+				a.Equal(`{
+  count: -3
+  status: STATUS_ACTIVE,
+  [ext.flag]: true
+  ids: [1, 2]
+  names: []
+  limits {
+    max: 2.5
+  }
+  rules: [{
+    name: "a"
+  }, {
+  }];
 }`, string(got))
 			},
 		},
@@ -0,0 +1,71 @@
+package tfl
+
+import (
+	"github.com/activatedio/protogen"
+)
+
+// Value is the value of a field, rendered on the line of the field after its name. MessageValue is also a Value.
+type Value interface {
+	protogen.Renderer
+}
+
+// literalValue is a value which is written as given.
+type literalValue struct {
+	text string
+}
+
+// Render writes the value to the provided Output.
+func (l *literalValue) Render(o protogen.Output) error {
+	return o.Write(l.text)
+}
+
+// NewStringValue creates a Value for a string, which is written in double quotes with its escapes as given.
+func NewStringValue(value string) Value {
+	return &literalValue{
+		text: `"` + value + `"`,
+	}
+}
+
+// NewScalarValue creates a Value for a number, boolean or enum value, which is written as given, such as -1, 0x1f,
+// 2.5, inf, true or STATUS_ACTIVE.
+func NewScalarValue(value string) Value {
+	return &literalValue{
+		text: value,
+	}
+}
+
+// listValue is a list of values of a repeated field.
+type listValue struct {
+	values []Value
+}
+
+// Render writes the values between square brackets, separated by commas, to the provided Output.
+func (l *listValue) Render(o protogen.Output) error {
+
+	err := o.Write("[")
+	if err != nil {
+		return err
+	}
+
+	for n, v := range l.values {
+		if n > 0 {
+			err = o.Write(", ")
+			if err != nil {
+				return err
+			}
+		}
+		err = v.Render(o)
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.Write("]")
+}
+
+// NewListValue creates a Value for the values of a repeated field, such as [1, 2, 3].
+func NewListValue(values ...Value) Value {
+	return &listValue{
+		values: values,
+	}
+}